@@ -74,7 +74,7 @@ func (c *Context) Response() http.ContextResponse {
 }
 
 func (c *Context) WithValue(key any, value any) {
-	if c.values == nil {
+	if c.sharedValues() == nil {
 		c.values = make(map[any]any)
 		c.instance.Locals(sharedValuesKey, c.values)
 	}
@@ -91,8 +91,8 @@ func (c *Context) Context() context.Context {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	for key, value := range c.values {
-		if key == sessionKey {
+	for key, value := range c.sharedValues() {
+		if key == sessionKey || key == cspNonceKey {
 			continue
 		}
 		ctx = context.WithValue(ctx, key, value)
@@ -113,7 +113,7 @@ func (c *Context) Err() error {
 }
 
 func (c *Context) Value(key any) any {
-	if c.values != nil {
+	if c.sharedValues() != nil {
 		if v, ok := c.values[key]; ok {
 			return v
 		}
@@ -121,6 +121,16 @@ func (c *Context) Value(key any) any {
 	return c.Context().Value(key)
 }
 
+// sharedValues picks up the values created by a later handler of the same request, e.g. a value set by a route
+// middleware is visible to the recover middleware which wraps it.
+func (c *Context) sharedValues() map[any]any {
+	if c.values == nil {
+		c.values, _ = c.instance.Locals(sharedValuesKey).(map[any]any)
+	}
+
+	return c.values
+}
+
 func (c *Context) Instance() fiber.Ctx {
 	return c.instance
 }
@@ -2,6 +2,8 @@ package fiber
 
 import (
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"net/http"
@@ -119,6 +121,50 @@ func (r *ContextRequest) BindQuery(obj any) error {
 	return r.instance.Bind().Query(obj)
 }
 
+// ClientCertificates returns the verified certificate chain presented by the client, leaf first.
+// It is empty unless the request arrived over a mutual TLS connection and the client certificate was verified.
+// Only the verified chains are read, so in the "request" and "verify_if_given" modes of http.tls.client_auth.mode
+// a certificate the server didn't verify never shows up.
+func (r *ContextRequest) ClientCertificates() []*x509.Certificate {
+	state := r.instance.RequestCtx().TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return nil
+	}
+
+	return state.VerifiedChains[0]
+}
+
+// ClientSubject returns the subject of the verified client certificate.
+func (r *ContextRequest) ClientSubject() (pkix.Name, bool) {
+	certificates := r.ClientCertificates()
+	if len(certificates) == 0 {
+		return pkix.Name{}, false
+	}
+
+	return certificates[0].Subject, true
+}
+
+// ClientCertificates returns the verified certificate chain presented by the client of the request, leaf first,
+// see ContextRequest.ClientCertificates.
+func ClientCertificates(ctx contractshttp.Context) []*x509.Certificate {
+	request, ok := ctx.Request().(*ContextRequest)
+	if !ok {
+		return nil
+	}
+
+	return request.ClientCertificates()
+}
+
+// ClientSubject returns the subject of the verified client certificate of the request.
+func ClientSubject(ctx contractshttp.Context) (pkix.Name, bool) {
+	request, ok := ctx.Request().(*ContextRequest)
+	if !ok {
+		return pkix.Name{}, false
+	}
+
+	return request.ClientSubject()
+}
+
 func (r *ContextRequest) Cookie(key string, defaultValue ...string) string {
 	return r.instance.Cookies(key, defaultValue...)
 }
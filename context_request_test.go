@@ -12,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gofiber/fiber/v3"
 	contractshttp "github.com/goravel/framework/contracts/http"
 	frameworkfilesystem "github.com/goravel/framework/filesystem"
 	foundationjson "github.com/goravel/framework/foundation/json"
@@ -46,7 +47,23 @@ func (s *ContextRequestSuite) SetupTest() {
 	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
 	s.mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
 	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
 	s.mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
 	s.mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
 	ValidationFacade = validation.NewValidation()
 
@@ -281,6 +298,23 @@ func (s *ContextRequestSuite) TestAll_DeleteWithJson() {
 	s.Equal(http.StatusOK, code)
 }
 
+func (s *ContextRequestSuite) TestClientCertificates() {
+	s.route.Get("/client/certificates", func(ctx contractshttp.Context) contractshttp.Response {
+		_, ok := ClientSubject(ctx)
+		return ctx.Response().Success().Json(contractshttp.Json{
+			"certificates": len(ClientCertificates(ctx)),
+			"subject":      ok,
+		})
+	})
+
+	req, err := http.NewRequest("GET", "/client/certificates", nil)
+	s.Require().Nil(err)
+	code, body, _, _ := s.request(req)
+
+	s.Equal("{\"certificates\":0,\"subject\":false}", body)
+	s.Equal(http.StatusOK, code)
+}
+
 func (s *ContextRequestSuite) TestBind_Json() {
 	s.route.Post("/bind/json/{id}", func(ctx contractshttp.Context) contractshttp.Response {
 		id := ctx.Request().Input("id")
@@ -6,7 +6,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/utils/v2"
@@ -56,6 +59,21 @@ func (r *ContextResponse) Download(filepath, filename string) contractshttp.Resp
 	return &DownloadResponse{filename, filepath, r.instance}
 }
 
+// ETag sets an explicit ETag, the value is quoted if it isn't, weak marks it as a weak validator.
+// The ETag middleware uses it instead of hashing the body.
+func (r *ContextResponse) ETag(etag string, weak ...bool) *ContextResponse {
+	etag = strings.TrimPrefix(etag, "W/")
+	if !strings.HasPrefix(etag, `"`) {
+		etag = strconv.Quote(etag)
+	}
+	if len(weak) > 0 && weak[0] {
+		etag = "W/" + etag
+	}
+	r.instance.Set(fiber.HeaderETag, etag)
+
+	return r
+}
+
 func (r *ContextResponse) File(filepath string) contractshttp.Response {
 	return &FileResponse{filepath, r.instance}
 }
@@ -70,6 +88,13 @@ func (r *ContextResponse) Json(code int, obj any) contractshttp.AbortableRespons
 	return &JsonResponse{code, obj, r.instance}
 }
 
+// LastModified sets the Last-Modified header, the ETag middleware uses it for If-Modified-Since.
+func (r *ContextResponse) LastModified(t time.Time) *ContextResponse {
+	r.instance.Set(fiber.HeaderLastModified, t.UTC().Format(http.TimeFormat))
+
+	return r
+}
+
 func (r *ContextResponse) NoContent(code ...int) contractshttp.AbortableResponse {
 	if len(code) == 0 {
 		code = append(code, http.StatusNoContent)
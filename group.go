@@ -5,8 +5,11 @@ import (
 	"io/fs"
 	"net/http"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"reflect"
+	"strings"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/static"
@@ -19,6 +22,7 @@ import (
 
 type Group struct {
 	config              config.Config
+	hooks               *Hooks
 	instance            *fiber.App
 	prefix              string
 	middlewares         []contractshttp.Middleware
@@ -37,8 +41,16 @@ func NewGroup(config config.Config, instance *fiber.App, prefix string, middlewa
 }
 
 func (r *Group) Group(handler contractsroute.GroupFunc) {
+	if err := r.hooks.executeOnGroup(GroupInfo{
+		Prefix:     r.getFullPath(""),
+		Middleware: r.excludeMiddlewares(append(r.middlewares, r.lastMiddlewares...)),
+	}); err != nil {
+		panic(err)
+	}
+
 	handler(&Group{
 		config:              r.config,
+		hooks:               r.hooks,
 		instance:            r.instance,
 		prefix:              r.getFullPath(""),
 		middlewares:         r.middlewares,
@@ -50,6 +62,7 @@ func (r *Group) Group(handler contractsroute.GroupFunc) {
 func (r *Group) Prefix(path string) contractsroute.Router {
 	return &Group{
 		config:              r.config,
+		hooks:               r.hooks,
 		instance:            r.instance,
 		prefix:              r.getFullPath(path),
 		middlewares:         r.middlewares,
@@ -61,6 +74,7 @@ func (r *Group) Prefix(path string) contractsroute.Router {
 func (r *Group) Middleware(middlewares ...contractshttp.Middleware) contractsroute.Router {
 	return &Group{
 		config:              r.config,
+		hooks:               r.hooks,
 		instance:            r.instance,
 		prefix:              r.getFullPath(""),
 		middlewares:         append(r.middlewares, middlewares...),
@@ -72,6 +86,7 @@ func (r *Group) Middleware(middlewares ...contractshttp.Middleware) contractsrou
 func (r *Group) WithoutMiddleware(middlewares ...contractshttp.Middleware) contractsroute.Router {
 	return &Group{
 		config:              r.config,
+		hooks:               r.hooks,
 		instance:            r.instance,
 		prefix:              r.getFullPath(""),
 		middlewares:         r.middlewares,
@@ -84,49 +99,49 @@ func (r *Group) Any(path string, handler contractshttp.HandlerFunc) contractsrou
 	first, rest := fiberHandlerArgs(r.getMiddlewares(handler))
 	r.instance.All(r.getFiberFullPath(path), first, rest...)
 
-	return NewAction(contractshttp.MethodAny, r.getFullPath(path), r.getHandlerName(handler))
+	return r.newAction(contractshttp.MethodAny, r.getFullPath(path), r.getHandlerName(handler))
 }
 
 func (r *Group) Get(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
 	first, rest := fiberHandlerArgs(r.getMiddlewares(handler))
 	r.instance.Get(r.getFiberFullPath(path), first, rest...)
 
-	return NewAction(contractshttp.MethodGet, r.getFullPath(path), r.getHandlerName(handler))
+	return r.newAction(contractshttp.MethodGet, r.getFullPath(path), r.getHandlerName(handler))
 }
 
 func (r *Group) Post(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
 	first, rest := fiberHandlerArgs(r.getMiddlewares(handler))
 	r.instance.Post(r.getFiberFullPath(path), first, rest...)
 
-	return NewAction(contractshttp.MethodPost, r.getFullPath(path), r.getHandlerName(handler))
+	return r.newAction(contractshttp.MethodPost, r.getFullPath(path), r.getHandlerName(handler))
 }
 
 func (r *Group) Delete(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
 	first, rest := fiberHandlerArgs(r.getMiddlewares(handler))
 	r.instance.Delete(r.getFiberFullPath(path), first, rest...)
 
-	return NewAction(contractshttp.MethodDelete, r.getFullPath(path), r.getHandlerName(handler))
+	return r.newAction(contractshttp.MethodDelete, r.getFullPath(path), r.getHandlerName(handler))
 }
 
 func (r *Group) Patch(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
 	first, rest := fiberHandlerArgs(r.getMiddlewares(handler))
 	r.instance.Patch(r.getFiberFullPath(path), first, rest...)
 
-	return NewAction(contractshttp.MethodPatch, r.getFullPath(path), r.getHandlerName(handler))
+	return r.newAction(contractshttp.MethodPatch, r.getFullPath(path), r.getHandlerName(handler))
 }
 
 func (r *Group) Put(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
 	first, rest := fiberHandlerArgs(r.getMiddlewares(handler))
 	r.instance.Put(r.getFiberFullPath(path), first, rest...)
 
-	return NewAction(contractshttp.MethodPut, r.getFullPath(path), r.getHandlerName(handler))
+	return r.newAction(contractshttp.MethodPut, r.getFullPath(path), r.getHandlerName(handler))
 }
 
 func (r *Group) Options(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
 	first, rest := fiberHandlerArgs(r.getMiddlewares(handler))
 	r.instance.Options(r.getFiberFullPath(path), first, rest...)
 
-	return NewAction(contractshttp.MethodOptions, r.getFullPath(path), r.getHandlerName(handler))
+	return r.newAction(contractshttp.MethodOptions, r.getFullPath(path), r.getHandlerName(handler))
 }
 
 func (r *Group) Resource(path string, controller contractshttp.ResourceController) contractsroute.Action {
@@ -146,14 +161,14 @@ func (r *Group) Resource(path string, controller contractshttp.ResourceControlle
 	first, rest = fiberHandlerArgs(r.getMiddlewares(controller.Destroy))
 	r.instance.Delete(fullPathWithID, first, rest...)
 
-	return NewAction(contractshttp.MethodResource, r.getFullPath(path), r.getHandlerName(controller))
+	return r.newAction(contractshttp.MethodResource, r.getFullPath(path), r.getHandlerName(controller))
 }
 
 func (r *Group) Static(path, root string) contractsroute.Action {
 	fullPath := r.getFiberFullPath(path)
-	r.instance.Use(fullPath, static.New(root, static.Config{Browse: false}))
+	r.useStatic(fullPath, os.DirFS(root), static.New(root, static.Config{Browse: false}))
 
-	return NewAction(contractshttp.MethodStatic, r.getFullPath(path), r.getHandlerName(nil))
+	return r.newAction(contractshttp.MethodStatic, r.getFullPath(path), r.getHandlerName(nil))
 }
 
 func (r *Group) StaticFile(path, filePath string) contractsroute.Action {
@@ -166,14 +181,35 @@ func (r *Group) StaticFile(path, filePath string) contractsroute.Action {
 		return c.SendFile(escapedPath)
 	})
 
-	return NewAction(contractshttp.MethodStaticFile, r.getFullPath(path), r.getHandlerName(nil))
+	return r.newAction(contractshttp.MethodStaticFile, r.getFullPath(path), r.getHandlerName(nil))
 }
 
 func (r *Group) StaticFS(path string, fileSystem http.FileSystem) contractsroute.Action {
 	fullPath := r.getFiberFullPath(path)
-	r.instance.Use(fullPath, static.New("", static.Config{FS: httpFSToFS{fileSystem}}))
+	fileFS := httpFSToFS{fileSystem}
+	r.useStatic(fullPath, fileFS, static.New("", static.Config{FS: fileFS}))
+
+	return r.newAction(contractshttp.MethodStaticFS, r.getFullPath(path), r.getHandlerName(nil))
+}
 
-	return NewAction(contractshttp.MethodStaticFS, r.getFullPath(path), r.getHandlerName(nil))
+// useStatic serves the files under the path, the precompressed siblings are served first when
+// http.drivers.fiber.static_precompressed is enabled.
+func (r *Group) useStatic(fullPath string, fileSystem fs.FS, handler fiber.Handler) {
+	if r.config != nil && r.config.GetBool("http.drivers.fiber.static_precompressed", false) {
+		r.instance.Use(fullPath, precompressedStatic(fullPath, fileSystem), handler)
+		return
+	}
+
+	r.instance.Use(fullPath, handler)
+}
+
+// newAction registers the route info, the OnRoute hooks run for it when the server starts.
+func (r *Group) newAction(method, path, handler string) contractsroute.Action {
+	action := NewAction(method, path, handler)
+	info := action.(*Action)
+	r.hooks.addRoute(info.path, info.method)
+
+	return action
 }
 
 // httpFSToFS wraps an http.FileSystem to implement fs.FS for use with fiber's static middleware.
@@ -188,6 +224,52 @@ func (h httpFSToFS) Open(name string) (fs.File, error) {
 	return h.httpFS.Open(name)
 }
 
+// precompressedFileSuffixes maps the encodings to the suffixes of the precompressed siblings of static files.
+var precompressedFileSuffixes = map[string]string{
+	"zstd": ".zst",
+	"br":   ".br",
+	"gzip": ".gz",
+}
+
+// precompressedStatic serves the precompressed sibling of a static file, e.g. app.js.br for app.js,
+// if the client accepts its encoding, otherwise the request falls through to the static handler. The range
+// requests and the files without an extension, whose content type is unknown, fall through too.
+func precompressedStatic(prefix string, fileSystem fs.FS) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead || c.Get(fiber.HeaderRange) != "" {
+			return c.Next()
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(c.Path(), prefix)), "/")
+		extension := strings.TrimPrefix(path.Ext(name), ".")
+		if name == "" || name == "." || extension == "" {
+			return c.Next()
+		}
+
+		encoding := negotiateEncoding(c.Get(fiber.HeaderAcceptEncoding), []string{"zstd", "br", "gzip"})
+		if encoding == "" {
+			return c.Next()
+		}
+
+		file, err := fileSystem.Open(name + precompressedFileSuffixes[encoding])
+		if err != nil {
+			return c.Next()
+		}
+		info, err := file.Stat()
+		if err != nil || info.IsDir() {
+			_ = file.Close()
+			return c.Next()
+		}
+
+		c.Type(extension)
+		c.Set(fiber.HeaderContentEncoding, encoding)
+		c.Set(fiber.HeaderLastModified, info.ModTime().UTC().Format(http.TimeFormat))
+		appendVary(c, fiber.HeaderAcceptEncoding)
+
+		return c.SendStream(file, int(info.Size()))
+	}
+}
+
 func (r *Group) getMiddlewares(handler contractshttp.HandlerFunc) []fiber.Handler {
 	var middlewares []fiber.Handler
 	middlewares = middlewaresToFiberHandlers(r.excludeMiddlewares(append(r.middlewares, r.lastMiddlewares...)))
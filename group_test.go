@@ -38,7 +38,23 @@ func (s *GroupTestSuite) SetupTest() {
 	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
 	s.mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
 	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
 	s.mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
 	s.mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
 
 	s.route = &Route{
@@ -189,6 +205,7 @@ func (s *GroupTestSuite) TestStatic() {
 	err = os.WriteFile(filepath.Join(tempDir, "test.json"), []byte("{\"id\":1}"), 0755)
 	assert.NoError(s.T(), err)
 
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.static_precompressed", false).Return(false).Once()
 	s.route.Static("static", tempDir).Name("static")
 
 	s.assert("GET", "/static/test.json", http.StatusOK, "{\"id\":1}")
@@ -200,6 +217,37 @@ func (s *GroupTestSuite) TestStatic() {
 	}, s.route.Info("static"))
 }
 
+func (s *GroupTestSuite) TestStaticPrecompressed() {
+	tempDir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(tempDir, "test.json"), []byte("{\"id\":1}"), 0644))
+	s.Require().NoError(os.WriteFile(filepath.Join(tempDir, "test.json.br"), []byte("brotli"), 0644))
+
+	for _, enabled := range []bool{false, true} {
+		path := "static-disabled"
+		if enabled {
+			path = "static-enabled"
+		}
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.static_precompressed", false).Return(enabled).Once()
+		s.route.Static(path, tempDir)
+
+		req, err := http.NewRequest("GET", "/"+path+"/test.json", nil)
+		s.Require().NoError(err)
+		req.Header.Set("Accept-Encoding", "br")
+		resp, err := s.route.Test(req)
+		s.Require().NoError(err)
+
+		body, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		if enabled {
+			s.Equal("br", resp.Header.Get("Content-Encoding"))
+			s.Equal("brotli", string(body))
+		} else {
+			s.Empty(resp.Header.Get("Content-Encoding"))
+			s.Equal("{\"id\":1}", string(body))
+		}
+	}
+}
+
 func (s *GroupTestSuite) TestStaticFile() {
 	file, err := os.CreateTemp("", "test")
 	assert.NoError(s.T(), err)
@@ -225,6 +273,7 @@ func (s *GroupTestSuite) TestStaticFS() {
 	err = os.WriteFile(filepath.Join(tempDir, "test.json"), []byte("{\"id\":1}"), 0755)
 	assert.NoError(s.T(), err)
 
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.static_precompressed", false).Return(false).Once()
 	s.route.StaticFS("static-fs", http.Dir(tempDir)).Name("static-fs")
 
 	s.assert("GET", "/static-fs/test.json", http.StatusOK, "{\"id\":1}")
@@ -293,17 +342,6 @@ func (s *GroupTestSuite) TestMultiplePrefixGroupMiddleware() {
 }
 
 func (s *GroupTestSuite) TestGlobalMiddleware() {
-	s.mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
-	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
-	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
-	s.mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
-	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.body_limit", 4096).Return(4096).Once()
-	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
-	s.mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
-	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
-	s.mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
-	s.mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
-
 	s.route.GlobalMiddleware(&globalMiddlewareTestType{})
 	s.route.Get("/global-middleware", func(ctx contractshttp.Context) contractshttp.Response {
 		return ctx.Response().Json(http.StatusOK, contractshttp.Json{
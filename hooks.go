@@ -0,0 +1,138 @@
+package fiber
+
+import (
+	"errors"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+// ListenInfo describes the address the server starts listening on.
+type ListenInfo struct {
+	Host string
+	Port string
+	TLS  bool
+}
+
+// GroupInfo describes a route group when its routes are about to be registered.
+type GroupInfo struct {
+	Prefix     string
+	Middleware []contractshttp.Middleware
+}
+
+// Hooks lets packages react to the lifecycle of the HTTP server, e.g. emit startup logs,
+// register service discovery entries or warm caches.
+// Hooks 用于监听 HTTP 服务器的生命周期
+type Hooks struct {
+	onListen   []func(ListenInfo) error
+	onShutdown []func() error
+	onRoute    []func(contractshttp.Info) error
+	onGroup    []func(GroupInfo) error
+
+	// pendingRoutes are the routes the OnRoute hooks haven't run for yet, their info is read when the hooks run,
+	// so it includes the name and the excluded middleware set after registering the route.
+	pendingRoutes []pendingRoute
+}
+
+type pendingRoute struct {
+	path   string
+	method string
+}
+
+// OnListen runs when the server starts listening, an error aborts the startup with a panic.
+// OnListen 在服务器开始监听时执行
+func (h *Hooks) OnListen(handler ...func(ListenInfo) error) {
+	h.onListen = append(h.onListen, handler...)
+}
+
+// OnShutdown runs before the server shuts down, errors are returned by Route.Shutdown.
+// OnShutdown 在服务器关闭前执行
+func (h *Hooks) OnShutdown(handler ...func() error) {
+	h.onShutdown = append(h.onShutdown, handler...)
+}
+
+// OnRoute runs for every registered route when the server starts, or when Route.Test is called, so the route info
+// is complete. An error aborts the startup and is returned by the Run and Listen methods.
+// OnRoute 在服务器启动时为每个已注册的路由执行
+func (h *Hooks) OnRoute(handler ...func(contractshttp.Info) error) {
+	h.onRoute = append(h.onRoute, handler...)
+}
+
+// OnGroup runs when a route group is registered via Group.
+// OnGroup 在注册路由组时执行
+func (h *Hooks) OnGroup(handler ...func(GroupInfo) error) {
+	h.onGroup = append(h.onGroup, handler...)
+}
+
+func (h *Hooks) executeOnListen(info ListenInfo) error {
+	if h == nil {
+		return nil
+	}
+
+	for _, handler := range h.onListen {
+		if err := handler(info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// executeOnShutdown runs every handler even if one fails, so a broken hook can't block the shutdown.
+func (h *Hooks) executeOnShutdown() error {
+	if h == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, handler := range h.onShutdown {
+		if err := handler(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h *Hooks) addRoute(path, method string) {
+	if h == nil {
+		return
+	}
+
+	h.pendingRoutes = append(h.pendingRoutes, pendingRoute{path: path, method: method})
+}
+
+// executeOnRoute runs the handlers for the pending routes, a route is removed once every handler succeeds for it,
+// so the route failing a handler and the ones after it are passed to the handlers again on the next call.
+func (h *Hooks) executeOnRoute() error {
+	if h == nil {
+		return nil
+	}
+
+	// The handlers may register routes, they are appended to the pending routes.
+	for len(h.pendingRoutes) > 0 {
+		route := h.pendingRoutes[0]
+		for _, handler := range h.onRoute {
+			if err := handler(routes[route.path][route.method]); err != nil {
+				return err
+			}
+		}
+		h.pendingRoutes = h.pendingRoutes[1:]
+	}
+	h.pendingRoutes = nil
+
+	return nil
+}
+
+func (h *Hooks) executeOnGroup(info GroupInfo) error {
+	if h == nil {
+		return nil
+	}
+
+	for _, handler := range h.onGroup {
+		if err := handler(info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
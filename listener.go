@@ -0,0 +1,66 @@
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// unixAddrPrefix marks an address as a Unix domain socket path, e.g. unix:/run/goravel.sock
+const unixAddrPrefix = "unix:"
+
+// listenFdsStart is the first file descriptor passed by systemd socket activation.
+// See https://www.freedesktop.org/software/systemd/man/latest/sd_listen_fds.html
+var listenFdsStart = 3
+
+// unixSocketPath returns the socket path if the address uses the unix: prefix.
+func unixSocketPath(addr string) (string, bool) {
+	path, ok := strings.CutPrefix(addr, unixAddrPrefix)
+	if !ok || path == "" {
+		return "", false
+	}
+
+	return path, true
+}
+
+// activatedListener returns a listener inherited from systemd socket activation (LISTEN_FDS).
+// The listener is looked up by its name in LISTEN_FDNAMES first, then by position.
+func activatedListener(name string, position int) (net.Listener, error) {
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil, errors.New("no listener is passed by socket activation")
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, errors.New("no listener is passed by socket activation")
+	}
+
+	index := position
+	for i, fdName := range strings.Split(os.Getenv("LISTEN_FDNAMES"), ":") {
+		if fdName == name && i < count {
+			index = i
+			break
+		}
+	}
+	if index >= count {
+		return nil, fmt.Errorf("socket activation passes %d listener(s), %s listener not found", count, name)
+	}
+
+	file := os.NewFile(uintptr(listenFdsStart+index), name)
+	if file == nil {
+		return nil, fmt.Errorf("invalid %s listener file descriptor", name)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	l, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("inherit %s listener failed: %w", name, err)
+	}
+
+	return l, nil
+}
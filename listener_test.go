@@ -0,0 +1,95 @@
+//go:build !windows
+
+package fiber
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivatedListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = l.Close()
+	}()
+
+	// inherit duplicates the listener into a bare file descriptor, the same as systemd passes to the process.
+	inherit := func() {
+		file, err := l.(*net.TCPListener).File()
+		require.NoError(t, err)
+		fd, err := syscall.Dup(int(file.Fd()))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		listenFdsStart = fd
+	}
+	defer func() {
+		listenFdsStart = 3
+	}()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		listener    string
+		position    int
+		expectError string
+	}{
+		{
+			name:        "not activated",
+			env:         map[string]string{"LISTEN_PID": "", "LISTEN_FDS": ""},
+			listener:    "http",
+			expectError: "no listener is passed by socket activation",
+		},
+		{
+			name:        "another process",
+			env:         map[string]string{"LISTEN_PID": strconv.Itoa(os.Getpid() + 1), "LISTEN_FDS": "1"},
+			listener:    "http",
+			expectError: "no listener is passed by socket activation",
+		},
+		{
+			name:     "by position",
+			env:      map[string]string{"LISTEN_PID": strconv.Itoa(os.Getpid()), "LISTEN_FDS": "1", "LISTEN_FDNAMES": ""},
+			listener: "http",
+		},
+		{
+			name:     "by name",
+			env:      map[string]string{"LISTEN_PID": strconv.Itoa(os.Getpid()), "LISTEN_FDS": "1", "LISTEN_FDNAMES": "https"},
+			listener: "https",
+			position: 1,
+		},
+		{
+			name:        "not found",
+			env:         map[string]string{"LISTEN_PID": strconv.Itoa(os.Getpid()), "LISTEN_FDS": "1", "LISTEN_FDNAMES": "http"},
+			listener:    "https",
+			position:    1,
+			expectError: "socket activation passes 1 listener(s), https listener not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+			if test.expectError == "" {
+				inherit()
+			}
+
+			activated, err := activatedListener(test.listener, test.position)
+			if test.expectError != "" {
+				assert.EqualError(t, err, test.expectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, l.Addr().String(), activated.Addr().String())
+			assert.NoError(t, activated.Close())
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package fiber
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	metricsDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	metricsSizeBuckets     = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+)
+
+// httpMetrics is the registry of the HTTP metrics collected by the Metrics middleware, it's exposed in the
+// Prometheus text exposition format by MetricsHandler.
+var httpMetrics = newMetricsRegistry()
+
+// metricsLabels are the labels of a series, the route is the route name or the path template, so the number of
+// series is bounded by the number of routes.
+type metricsLabels struct {
+	method string
+	route  string
+	status string
+}
+
+type metricsHistogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *metricsHistogram) observe(buckets []float64, value float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(buckets))
+	}
+	for i, bucket := range buckets {
+		if value <= bucket {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+type metricsRegistry struct {
+	mu            sync.Mutex
+	requests      map[metricsLabels]uint64
+	durations     map[metricsLabels]*metricsHistogram
+	requestSizes  map[metricsLabels]*metricsHistogram
+	responseSizes map[metricsLabels]*metricsHistogram
+	inFlight      map[string]int64
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		requests:      make(map[metricsLabels]uint64),
+		durations:     make(map[metricsLabels]*metricsHistogram),
+		requestSizes:  make(map[metricsLabels]*metricsHistogram),
+		responseSizes: make(map[metricsLabels]*metricsHistogram),
+		inFlight:      make(map[string]int64),
+	}
+}
+
+func (r *metricsRegistry) addInFlight(method string, delta int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inFlight[method] += delta
+}
+
+func (r *metricsRegistry) observe(labels metricsLabels, seconds float64, requestSize, responseSize int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests[labels]++
+	histogram(r.durations, labels).observe(metricsDurationBuckets, seconds)
+	// The request size is known before the response, so it isn't labeled by the status.
+	histogram(r.requestSizes, metricsLabels{method: labels.method, route: labels.route}).observe(metricsSizeBuckets, float64(requestSize))
+	histogram(r.responseSizes, labels).observe(metricsSizeBuckets, float64(responseSize))
+}
+
+// write writes the metrics in the Prometheus text exposition format, the series are sorted by their labels.
+func (r *metricsRegistry) write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buffer := bufio.NewWriter(w)
+
+	buffer.WriteString("# HELP http_requests_total The total number of HTTP requests.\n")
+	buffer.WriteString("# TYPE http_requests_total counter\n")
+	for _, labels := range sortedMetricsLabels(r.requests) {
+		fmt.Fprintf(buffer, "http_requests_total{%s} %d\n", labels.format(), r.requests[labels])
+	}
+
+	buffer.WriteString("# HELP http_requests_in_flight The number of HTTP requests being served.\n")
+	buffer.WriteString("# TYPE http_requests_in_flight gauge\n")
+	methods := make([]string, 0, len(r.inFlight))
+	for method := range r.inFlight {
+		methods = append(methods, method)
+	}
+	slices.Sort(methods)
+	for _, method := range methods {
+		fmt.Fprintf(buffer, "http_requests_in_flight{method=%s} %d\n", quoteMetricsLabel(method), r.inFlight[method])
+	}
+
+	writeMetricsHistograms(buffer, "http_request_duration_seconds", "The HTTP request latencies in seconds.", metricsDurationBuckets, r.durations)
+	writeMetricsHistograms(buffer, "http_request_size_bytes", "The HTTP request sizes in bytes.", metricsSizeBuckets, r.requestSizes)
+	writeMetricsHistograms(buffer, "http_response_size_bytes", "The HTTP response sizes in bytes.", metricsSizeBuckets, r.responseSizes)
+
+	return buffer.Flush()
+}
+
+func (l metricsLabels) format() string {
+	parts := []string{
+		"method=" + quoteMetricsLabel(l.method),
+		"route=" + quoteMetricsLabel(l.route),
+	}
+	if l.status != "" {
+		parts = append(parts, "status="+quoteMetricsLabel(l.status))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func histogram(histograms map[metricsLabels]*metricsHistogram, labels metricsLabels) *metricsHistogram {
+	h, ok := histograms[labels]
+	if !ok {
+		h = &metricsHistogram{}
+		histograms[labels] = h
+	}
+
+	return h
+}
+
+func writeMetricsHistograms(w *bufio.Writer, name, help string, buckets []float64, histograms map[metricsLabels]*metricsHistogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, labels := range sortedMetricsLabels(histograms) {
+		h := histograms[labels]
+		formatted := labels.format()
+		for i, bucket := range buckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, formatted, formatMetricsFloat(bucket), h.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, formatted, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, formatted, formatMetricsFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, formatted, h.count)
+	}
+}
+
+func sortedMetricsLabels[V any](series map[metricsLabels]V) []metricsLabels {
+	labels := make([]metricsLabels, 0, len(series))
+	for label := range series {
+		labels = append(labels, label)
+	}
+	slices.SortFunc(labels, func(a, b metricsLabels) int {
+		return cmp.Or(cmp.Compare(a.route, b.route), cmp.Compare(a.method, b.method), cmp.Compare(a.status, b.status))
+	})
+
+	return labels
+}
+
+func formatMetricsFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quoteMetricsLabel quotes the label value as the exposition format expects, only the backslash, the double quote
+// and the line feed are escaped.
+func quoteMetricsLabel(value string) string {
+	return `"` + metricsLabelEscaper.Replace(value) + `"`
+}
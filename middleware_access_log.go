@@ -0,0 +1,103 @@
+package fiber
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/goravel/framework/contracts/config"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/spf13/cast"
+)
+
+var accessLogSample = rand.Float64
+
+// accessLogMiddleware writes a structured entry for every request via LogFacade, it's enabled by
+// http.drivers.fiber.log.enabled and replaces the text logger of the debug mode.
+type accessLogMiddleware struct {
+	// sampleRate is the ratio of the requests to log, the server errors and the slow requests are always logged.
+	sampleRate float64
+	except     []string
+	// slowThreshold logs the slower requests as warnings, 0 disables it.
+	slowThreshold time.Duration
+}
+
+func newAccessLogMiddleware(config config.Config, driver string) *accessLogMiddleware {
+	sampleRate := cast.ToFloat64(config.Get(fmt.Sprintf("http.drivers.%s.log.sample_rate", driver), 1.0))
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+	except, _ := config.Get(fmt.Sprintf("http.drivers.%s.log.except", driver)).([]string)
+
+	return &accessLogMiddleware{
+		sampleRate:    sampleRate,
+		except:        trimPathPatterns(except),
+		slowThreshold: time.Duration(config.GetInt(fmt.Sprintf("http.drivers.%s.log.slow_threshold", driver), 0)) * time.Millisecond,
+	}
+}
+
+func (m *accessLogMiddleware) Signature() string {
+	return "goravel:access_log"
+}
+
+func (m *accessLogMiddleware) Handle(ctx contractshttp.Context) {
+	request := ctx.Request()
+	if matchPathPatterns(m.except, request.Path()) {
+		request.Next()
+		return
+	}
+
+	start := time.Now()
+	request.Next()
+	latency := time.Since(start)
+
+	c := ctx.(*Context).Instance()
+	if invalidFiber(c) {
+		return
+	}
+
+	response := c.Response()
+	status := response.StatusCode()
+	slow := m.slowThreshold > 0 && latency >= m.slowThreshold
+	if status < contractshttp.StatusInternalServerError && !slow && m.sampleRate < 1 && accessLogSample() >= m.sampleRate {
+		return
+	}
+
+	// The route is resolved after the handlers ran, the global middleware alone doesn't know it.
+	var name string
+	path := matchedRoute(c)
+	if path != "" {
+		name = request.Name()
+	}
+	data := map[string]any{
+		"method":     request.Method(),
+		"route":      name,
+		"path":       path,
+		"uri":        string(c.Request().RequestURI()),
+		"status":     status,
+		"latency_ms": float64(latency.Microseconds()) / 1000,
+		"ip":         request.Ip(),
+		"user_agent": c.Get(fiber.HeaderUserAgent),
+	}
+	// Reading the body of a stream would consume it, its size is only known from Content-Length.
+	if !response.IsBodyStream() {
+		data["bytes"] = len(response.Body())
+	} else if length := response.Header.ContentLength(); length >= 0 {
+		data["bytes"] = length
+	}
+	if id := GetRequestID(ctx); id != "" {
+		data["request_id"] = id
+	}
+
+	message := fmt.Sprintf("%s %s %d %s", request.Method(), string(c.Request().RequestURI()), status, latency)
+	writer := LogFacade.WithContext(ctx).With(data)
+	switch {
+	case status >= contractshttp.StatusInternalServerError:
+		writer.Error(message)
+	case slow:
+		writer.Warning(message)
+	default:
+		writer.Info(message)
+	}
+}
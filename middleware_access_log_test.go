@@ -0,0 +1,149 @@
+package fiber
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mocksconfig "github.com/goravel/framework/mocks/config"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	defer func() {
+		accessLogSample = rand.Float64
+	}()
+
+	tests := []struct {
+		name       string
+		middleware *accessLogMiddleware
+		path       string
+		global     bool
+		sample     float64
+		handler    contractshttp.HandlerFunc
+		expectLog  string
+		expectData map[string]any
+	}{
+		{
+			name:       "info",
+			middleware: &accessLogMiddleware{sampleRate: 1},
+			path:       "/users?page=1",
+			expectLog:  "Info",
+			expectData: map[string]any{
+				"method":     "GET",
+				"path":       "/*",
+				"uri":        "/users?page=1",
+				"status":     http.StatusOK,
+				"bytes":      2,
+				"ip":         "0.0.0.0",
+				"user_agent": "goravel",
+				"request_id": "generated",
+			},
+		},
+		{
+			name:       "unmatched route",
+			middleware: &accessLogMiddleware{sampleRate: 1},
+			path:       "/missing",
+			global:     true,
+			expectLog:  "Info",
+			expectData: map[string]any{"route": "", "path": "", "status": http.StatusNotFound},
+		},
+		{
+			name:       "excluded path",
+			middleware: &accessLogMiddleware{sampleRate: 1, except: trimPathPatterns([]string{"/health"})},
+			path:       "/health",
+		},
+		{
+			name:       "sampled out",
+			middleware: &accessLogMiddleware{sampleRate: 0.5},
+			sample:     0.5,
+		},
+		{
+			name:       "sampled in",
+			middleware: &accessLogMiddleware{sampleRate: 0.5},
+			sample:     0.4,
+			expectLog:  "Info",
+		},
+		{
+			name:       "server errors are always logged",
+			middleware: &accessLogMiddleware{sampleRate: 0.5},
+			sample:     0.9,
+			handler: func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().String(http.StatusBadGateway, "bad gateway")
+			},
+			expectLog:  "Error",
+			expectData: map[string]any{"status": http.StatusBadGateway, "bytes": 11},
+		},
+		{
+			name:       "slow requests are always logged",
+			middleware: &accessLogMiddleware{sampleRate: 0.5, slowThreshold: 10 * time.Millisecond},
+			sample:     0.9,
+			handler: func(ctx contractshttp.Context) contractshttp.Response {
+				time.Sleep(20 * time.Millisecond)
+				return ctx.Response().String(http.StatusOK, "ok")
+			},
+			expectLog: "Warning",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			accessLogSample = func() float64 {
+				return test.sample
+			}
+
+			mockLog := mockslog.NewLog(t)
+			mockWriter := mockslog.NewWriter(t)
+			LogFacade = mockLog
+			if test.expectLog != "" {
+				mockLog.EXPECT().WithContext(mock.Anything).Return(mockLog).Once()
+				mockLog.EXPECT().With(mock.MatchedBy(func(data map[string]any) bool {
+					for key, value := range test.expectData {
+						if data[key] != value {
+							return false
+						}
+					}
+					_, ok := data["latency_ms"].(float64)
+					return ok
+				})).Return(mockWriter).Once()
+				mockWriter.On(test.expectLog, mock.AnythingOfType("string")).Return().Once()
+			}
+
+			newApp := newMiddlewareTestApp
+			if test.global {
+				newApp = newGlobalMiddlewareTestApp
+			}
+			app := newApp(test.handler, RequestID(RequestIDConfig{Generator: func() string {
+				return "generated"
+			}}), test.middleware)
+
+			path := test.path
+			if path == "" {
+				path = "/"
+			}
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("User-Agent", "goravel")
+			_, err := app.Test(req)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewAccessLogMiddleware(t *testing.T) {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().Get("http.drivers.fiber.log.sample_rate", 1.0).Return(0.25).Once()
+	mockConfig.EXPECT().Get("http.drivers.fiber.log.except").Return([]string{"/health/"}).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.log.slow_threshold", 0).Return(500).Once()
+
+	assert.Equal(t, &accessLogMiddleware{
+		sampleRate:    0.25,
+		except:        []string{"health"},
+		slowThreshold: 500 * time.Millisecond,
+	}, newAccessLogMiddleware(mockConfig, "fiber"))
+}
@@ -0,0 +1,233 @@
+package fiber
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+type authPrincipalKeyType string
+
+// authPrincipalKey is a string type, so the log entries label the value as auth_principal.
+const authPrincipalKey authPrincipalKeyType = "auth_principal"
+
+// BasicAuthConfig configures the BasicAuth middleware, Users or Verifier is required.
+type BasicAuthConfig struct {
+	// Users maps the usernames to their passwords.
+	Users map[string]string
+	// Verifier checks the credentials of a user which isn't in Users, e.g. against a hashed password.
+	Verifier func(ctx contractshttp.Context, username, password string) bool
+	// Realm is sent in the WWW-Authenticate header.
+	// Default: Restricted
+	Realm string
+	// Response renders the response when the credentials are missing or invalid.
+	// Default: abort with 401 Unauthorized.
+	Response func(ctx contractshttp.Context)
+}
+
+type basicAuthMiddleware struct {
+	config BasicAuthConfig
+	// users keeps the hashes of the passwords, so the comparison takes the same time whatever their lengths.
+	users map[string][sha256.Size]byte
+}
+
+func (m *basicAuthMiddleware) Signature() string {
+	return "goravel:basic_auth"
+}
+
+func (m *basicAuthMiddleware) Handle(ctx contractshttp.Context) {
+	username, password, ok := parseBasicAuth(ctx.Request().Header(fiber.HeaderAuthorization))
+	if !ok || !m.verify(ctx, username, password) {
+		ctx.Response().Header(fiber.HeaderWWWAuthenticate, "Basic realm="+strconv.Quote(m.config.Realm)+`, charset="UTF-8"`)
+		unauthorized(ctx, m.config.Response)
+		return
+	}
+
+	ctx.WithValue(authPrincipalKey, username)
+	ctx.Request().Next()
+}
+
+func (m *basicAuthMiddleware) verify(ctx contractshttp.Context, username, password string) bool {
+	hash := sha256.Sum256([]byte(password))
+	if expected, ok := m.users[username]; ok {
+		return subtle.ConstantTimeCompare(hash[:], expected[:]) == 1
+	}
+	if m.config.Verifier != nil {
+		return m.config.Verifier(ctx, username, password)
+	}
+
+	return false
+}
+
+// BasicAuth creates middleware to authenticate requests with HTTP Basic credentials, the username is stored as
+// the principal, see GetAuthPrincipal. It panics if neither Users nor Verifier is set.
+func BasicAuth(config BasicAuthConfig) contractshttp.Middleware {
+	if len(config.Users) == 0 && config.Verifier == nil {
+		panic(errors.New("basic auth requires users or a verifier"))
+	}
+	if config.Realm == "" {
+		config.Realm = "Restricted"
+	}
+
+	users := make(map[string][sha256.Size]byte, len(config.Users))
+	for username, password := range config.Users {
+		users[username] = sha256.Sum256([]byte(password))
+	}
+
+	return &basicAuthMiddleware{config: config, users: users}
+}
+
+// BearerAuthConfig configures the BearerAuth middleware, Tokens or Verifier is required.
+type BearerAuthConfig struct {
+	// Tokens maps the accepted tokens to their principals, e.g. the name of the client.
+	Tokens map[string]string
+	// Verifier resolves the principal of a token which isn't in Tokens, ok is false if the token is invalid.
+	Verifier func(ctx contractshttp.Context, token string) (principal string, ok bool)
+	// Header carries the token, Authorization expects the Bearer scheme, other headers carry the bare token,
+	// e.g. X-API-Key.
+	// Default: Authorization
+	Header string
+	// Query is the query parameter which carries the token when the header is missing, e.g. api_key.
+	// Default: "", tokens are only read from the header.
+	Query string
+	// Realm is sent in the WWW-Authenticate header when Header is Authorization.
+	// Default: Restricted
+	Realm string
+	// Response renders the response when the token is missing or invalid.
+	// Default: abort with 401 Unauthorized.
+	Response func(ctx contractshttp.Context)
+}
+
+type bearerAuthToken struct {
+	hash      [sha256.Size]byte
+	principal string
+}
+
+type bearerAuthMiddleware struct {
+	config BearerAuthConfig
+	tokens []bearerAuthToken
+}
+
+func (m *bearerAuthMiddleware) Signature() string {
+	return "goravel:bearer_auth"
+}
+
+func (m *bearerAuthMiddleware) Handle(ctx contractshttp.Context) {
+	token := m.token(ctx)
+	principal, ok := "", false
+	if token != "" {
+		principal, ok = m.verify(ctx, token)
+	}
+	if !ok {
+		if strings.EqualFold(m.config.Header, fiber.HeaderAuthorization) {
+			challenge := "Bearer realm=" + strconv.Quote(m.config.Realm)
+			if token != "" {
+				challenge += `, error="invalid_token"`
+			}
+			ctx.Response().Header(fiber.HeaderWWWAuthenticate, challenge)
+		}
+		unauthorized(ctx, m.config.Response)
+		return
+	}
+
+	ctx.WithValue(authPrincipalKey, principal)
+	ctx.Request().Next()
+}
+
+func (m *bearerAuthMiddleware) token(ctx contractshttp.Context) string {
+	header := ctx.Request().Header(m.config.Header)
+	if strings.EqualFold(m.config.Header, fiber.HeaderAuthorization) {
+		scheme, token, found := strings.Cut(header, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		header = ""
+	}
+	if header != "" {
+		return header
+	}
+	if m.config.Query != "" {
+		return ctx.Request().Query(m.config.Query)
+	}
+
+	return ""
+}
+
+// verify compares the token with every configured token, so the time doesn't reveal which one shares a prefix.
+func (m *bearerAuthMiddleware) verify(ctx contractshttp.Context, token string) (string, bool) {
+	hash := sha256.Sum256([]byte(token))
+	principal, matched := "", false
+	for _, candidate := range m.tokens {
+		if subtle.ConstantTimeCompare(hash[:], candidate.hash[:]) == 1 {
+			principal, matched = candidate.principal, true
+		}
+	}
+	if matched {
+		return principal, true
+	}
+	if m.config.Verifier != nil {
+		return m.config.Verifier(ctx, token)
+	}
+
+	return "", false
+}
+
+// BearerAuth creates middleware to authenticate requests with static bearer tokens or API keys read from a header
+// or the query, the principal of the token is stored in the context values, see GetAuthPrincipal.
+// It panics if neither Tokens nor Verifier is set.
+func BearerAuth(config BearerAuthConfig) contractshttp.Middleware {
+	if len(config.Tokens) == 0 && config.Verifier == nil {
+		panic(errors.New("bearer auth requires tokens or a verifier"))
+	}
+	if config.Header == "" {
+		config.Header = fiber.HeaderAuthorization
+	}
+	if config.Realm == "" {
+		config.Realm = "Restricted"
+	}
+
+	tokens := make([]bearerAuthToken, 0, len(config.Tokens))
+	for token, principal := range config.Tokens {
+		tokens = append(tokens, bearerAuthToken{hash: sha256.Sum256([]byte(token)), principal: principal})
+	}
+
+	return &bearerAuthMiddleware{config: config, tokens: tokens}
+}
+
+// GetAuthPrincipal returns the principal authenticated by the BasicAuth or BearerAuth middleware, it accepts both
+// the http context and the context.Context derived from it.
+func GetAuthPrincipal(ctx context.Context) string {
+	principal, _ := ctx.Value(authPrincipalKey).(string)
+
+	return principal
+}
+
+func parseBasicAuth(header string) (username, password string, ok bool) {
+	scheme, encoded, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", false
+	}
+
+	return strings.Cut(string(decoded), ":")
+}
+
+func unauthorized(ctx contractshttp.Context, response func(ctx contractshttp.Context)) {
+	if response != nil {
+		response(ctx)
+		return
+	}
+
+	ctx.Request().Abort(contractshttp.StatusUnauthorized)
+}
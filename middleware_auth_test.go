@@ -0,0 +1,202 @@
+package fiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// principalHandler responds the principal stored by the auth middleware.
+func principalHandler(ctx contractshttp.Context) contractshttp.Response {
+	return ctx.Response().String(http.StatusOK, GetAuthPrincipal(ctx.Context()))
+}
+
+func TestBasicAuth(t *testing.T) {
+	config := BasicAuthConfig{
+		Users: map[string]string{"admin": "secret"},
+		Verifier: func(ctx contractshttp.Context, username, password string) bool {
+			return username == "ops" && password == "verified"
+		},
+		Realm: "Admin",
+	}
+
+	tests := []struct {
+		name            string
+		config          BasicAuthConfig
+		setup           func(req *http.Request)
+		expectStatus    int
+		expectBody      string
+		expectChallenge string
+	}{
+		{
+			name:         "user of the map",
+			config:       config,
+			setup:        func(req *http.Request) { req.SetBasicAuth("admin", "secret") },
+			expectStatus: http.StatusOK,
+			expectBody:   "admin",
+		},
+		{
+			name:         "user of the verifier",
+			config:       config,
+			setup:        func(req *http.Request) { req.SetBasicAuth("ops", "verified") },
+			expectStatus: http.StatusOK,
+			expectBody:   "ops",
+		},
+		{
+			name:            "wrong password",
+			config:          config,
+			setup:           func(req *http.Request) { req.SetBasicAuth("admin", "secrets") },
+			expectStatus:    http.StatusUnauthorized,
+			expectChallenge: `Basic realm="Admin", charset="UTF-8"`,
+		},
+		{
+			name:            "missing credentials",
+			config:          BasicAuthConfig{Users: map[string]string{"admin": "secret"}},
+			expectStatus:    http.StatusUnauthorized,
+			expectChallenge: `Basic realm="Restricted", charset="UTF-8"`,
+		},
+		{
+			name:            "malformed credentials",
+			config:          config,
+			setup:           func(req *http.Request) { req.Header.Set("Authorization", "Basic !!!") },
+			expectStatus:    http.StatusUnauthorized,
+			expectChallenge: `Basic realm="Admin", charset="UTF-8"`,
+		},
+		{
+			name: "custom response",
+			config: BasicAuthConfig{Users: map[string]string{"admin": "secret"}, Response: func(ctx contractshttp.Context) {
+				ctx.Response().String(http.StatusForbidden, "denied").Abort()
+			}},
+			expectStatus:    http.StatusForbidden,
+			expectBody:      "denied",
+			expectChallenge: `Basic realm="Restricted", charset="UTF-8"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newMiddlewareTestApp(principalHandler, BasicAuth(test.config))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if test.setup != nil {
+				test.setup(req)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+			assert.Equal(t, test.expectChallenge, resp.Header.Get("WWW-Authenticate"))
+			if test.expectBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectBody, string(body))
+			}
+		})
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	tokens := map[string]string{"token-a": "client-a", "token-b": "client-b"}
+
+	tests := []struct {
+		name            string
+		config          BearerAuthConfig
+		path            string
+		headers         map[string]string
+		expectStatus    int
+		expectBody      string
+		expectChallenge string
+	}{
+		{
+			name:         "bearer token",
+			config:       BearerAuthConfig{Tokens: tokens},
+			headers:      map[string]string{"Authorization": "Bearer token-b"},
+			expectStatus: http.StatusOK,
+			expectBody:   "client-b",
+		},
+		{
+			name:            "invalid token",
+			config:          BearerAuthConfig{Tokens: tokens, Realm: "api"},
+			headers:         map[string]string{"Authorization": "Bearer token-c"},
+			expectStatus:    http.StatusUnauthorized,
+			expectChallenge: `Bearer realm="api", error="invalid_token"`,
+		},
+		{
+			name:            "another scheme",
+			config:          BearerAuthConfig{Tokens: tokens},
+			headers:         map[string]string{"Authorization": "Basic dG9rZW4tYQ=="},
+			expectStatus:    http.StatusUnauthorized,
+			expectChallenge: `Bearer realm="Restricted"`,
+		},
+		{
+			name:         "api key header",
+			config:       BearerAuthConfig{Tokens: tokens, Header: "X-API-Key"},
+			headers:      map[string]string{"X-API-Key": "token-a"},
+			expectStatus: http.StatusOK,
+			expectBody:   "client-a",
+		},
+		{
+			name:         "query",
+			config:       BearerAuthConfig{Tokens: tokens, Query: "api_key"},
+			path:         "/?api_key=token-a",
+			expectStatus: http.StatusOK,
+			expectBody:   "client-a",
+		},
+		{
+			name:         "query is ignored by default",
+			config:       BearerAuthConfig{Tokens: tokens, Header: "X-API-Key"},
+			path:         "/?api_key=token-a",
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "verifier",
+			config: BearerAuthConfig{Verifier: func(ctx contractshttp.Context, token string) (string, bool) {
+				return "webhook", token == "signed"
+			}},
+			headers:      map[string]string{"Authorization": "bearer signed"},
+			expectStatus: http.StatusOK,
+			expectBody:   "webhook",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newMiddlewareTestApp(principalHandler, BearerAuth(test.config))
+
+			path := test.path
+			if path == "" {
+				path = "/"
+			}
+			req := httptest.NewRequest("GET", path, nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+			assert.Equal(t, test.expectChallenge, resp.Header.Get("WWW-Authenticate"))
+			if test.expectBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectBody, string(body))
+			}
+		})
+	}
+}
+
+func TestAuthConfigRequired(t *testing.T) {
+	assert.PanicsWithError(t, "basic auth requires users or a verifier", func() {
+		BasicAuth(BasicAuthConfig{})
+	})
+	assert.PanicsWithError(t, "bearer auth requires tokens or a verifier", func() {
+		BearerAuth(BearerAuthConfig{})
+	})
+	assert.Equal(t, "goravel:basic_auth", BasicAuth(BasicAuthConfig{Users: map[string]string{"a": "b"}}).Signature())
+	assert.Equal(t, "goravel:bearer_auth", BearerAuth(BearerAuthConfig{Tokens: map[string]string{"a": "b"}}).Signature())
+}
@@ -0,0 +1,514 @@
+package fiber
+
+import (
+	"bytes"
+	"fmt"
+	"iter"
+	"maps"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/json"
+)
+
+const (
+	redactedValue    = "[REDACTED]"
+	unredactableBody = "[unredactable body omitted]"
+)
+
+var (
+	defaultBodyCaptureRedactKeys = []string{
+		"password", "password_confirmation", "token", "access_token", "refresh_token", "secret", "client_secret", "api_key",
+	}
+	defaultBodyCaptureRedactHeaders = []string{
+		fiber.HeaderAuthorization, fiber.HeaderProxyAuthorization, fiber.HeaderCookie, fiber.HeaderSetCookie,
+		"X-CSRF-Token", "X-Api-Key",
+	}
+)
+
+var bodyCaptureSample = rand.Float64
+
+// BodyCaptureConfig configures the BodyCapture middleware, the zero value uses the defaults.
+type BodyCaptureConfig struct {
+	// MaxBodySize is the number of bytes captured of each body, the rest is cut off after the redaction.
+	// Default: 64 KB
+	MaxBodySize int
+	// SampleRate is the ratio of the requests to capture, between 0 and 1.
+	// Default: 1
+	SampleRate float64
+	// RedactKeys are the JSON keys at any depth, the form fields and the query parameters whose values are
+	// redacted, compared case-insensitively. They are added to the defaults: password, password_confirmation,
+	// token, access_token, refresh_token, secret, client_secret and api_key.
+	RedactKeys []string
+	// RedactHeaders are the request and response headers whose values are redacted. They are added to the
+	// defaults: Authorization, Proxy-Authorization, Cookie, Set-Cookie, X-CSRF-Token and X-Api-Key.
+	RedactHeaders []string
+	// CaptureText captures the text bodies which can't be redacted, e.g. text/plain, HTML and XML, cut off at
+	// MaxBodySize, the secrets in them are captured as they are. They are omitted by default.
+	CaptureText bool
+	// Directory writes every capture as a HAR file to the directory instead of logging it via LogFacade,
+	// the files can be opened by the browser developer tools.
+	Directory string
+}
+
+type bodyCaptureMiddleware struct {
+	config BodyCaptureConfig
+	// The keys and the headers are lowercase.
+	redactKeys    map[string]bool
+	redactHeaders map[string]bool
+}
+
+func (m *bodyCaptureMiddleware) Signature() string {
+	return "goravel:body_capture"
+}
+
+func (m *bodyCaptureMiddleware) Handle(ctx contractshttp.Context) {
+	request := ctx.Request()
+	if m.config.SampleRate < 1 && bodyCaptureSample() >= m.config.SampleRate {
+		request.Next()
+		return
+	}
+
+	c := ctx.(*Context).Instance()
+	start := time.Now()
+	// The request is captured before the handlers run, the buffers of fasthttp are reused afterwards.
+	harRequest := m.captureRequest(c)
+	request.Next()
+	latency := float64(time.Since(start).Microseconds()) / 1000
+
+	if invalidFiber(c) {
+		return
+	}
+
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            latency,
+		Request:         harRequest,
+		Response:        m.captureResponse(ctx, c),
+		Cache:           struct{}{},
+		Timings:         harTimings{Send: 0, Wait: latency, Receive: 0},
+	}
+
+	if m.config.Directory != "" {
+		if err := m.writeHAR(ctx, entry); err != nil {
+			LogFacade.Error(fmt.Errorf("body capture failed to write the HAR file: %w", err))
+		}
+		return
+	}
+
+	data := map[string]any{
+		"method":           entry.Request.Method,
+		"url":              entry.Request.URL,
+		"status":           entry.Response.Status,
+		"latency_ms":       latency,
+		"request_headers":  harHeadersMap(entry.Request.Headers),
+		"request_body":     "",
+		"response_headers": harHeadersMap(entry.Response.Headers),
+		"response_body":    entry.Response.Content.Text,
+	}
+	if postData := entry.Request.PostData; postData != nil {
+		data["request_body"] = postData.Text
+		if postData.Comment != "" {
+			data["request_body_comment"] = postData.Comment
+		}
+	}
+	if entry.Response.Content.Comment != "" {
+		data["response_body_comment"] = entry.Response.Content.Comment
+	}
+	if id := GetRequestID(ctx); id != "" {
+		data["request_id"] = id
+	}
+
+	LogFacade.WithContext(ctx).With(data).Info(fmt.Sprintf("captured %s %s %d", entry.Request.Method, entry.Request.URL, entry.Response.Status))
+}
+
+func (m *bodyCaptureMiddleware) captureRequest(c fiber.Ctx) harRequest {
+	query := url.Values{}
+	for key, value := range c.Request().URI().QueryArgs().All() {
+		query.Add(string(key), string(value))
+	}
+	m.redactValues(query)
+
+	captured := harRequest{
+		Method:      c.Method(),
+		URL:         c.BaseURL() + c.Path(),
+		HTTPVersion: c.Protocol(),
+		Cookies:     []harNameValue{},
+		Headers:     m.captureHeaders(c.Request().Header.All()),
+		QueryString: harNameValues(query),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if len(query) > 0 {
+		captured.URL += "?" + query.Encode()
+	}
+
+	contentType := string(c.Request().Header.ContentType())
+	switch {
+	case c.Request().IsBodyStream():
+		captured.PostData = &harPostData{MimeType: contentType, Comment: "streamed body omitted"}
+	case strings.HasPrefix(contentType, fiber.MIMEMultipartForm):
+		captured.BodySize = len(c.Request().Body())
+		form, err := c.MultipartForm()
+		if err != nil {
+			captured.PostData = &harPostData{MimeType: contentType, Text: unredactableBody}
+			break
+		}
+		// The files are replaced by their names in the curl syntax.
+		values := url.Values{}
+		for key, items := range form.Value {
+			values[key] = slices.Clone(items)
+		}
+		for key, files := range form.File {
+			for _, file := range files {
+				values.Add(key, "@"+file.Filename)
+			}
+		}
+		m.redactValues(values)
+		text, comment := m.truncate([]byte(values.Encode()))
+		captured.PostData = &harPostData{MimeType: contentType, Text: text, Comment: comment}
+	default:
+		body := c.Body()
+		captured.BodySize = len(body)
+		if len(body) > 0 {
+			text, comment := m.captureBody(body, contentType)
+			captured.PostData = &harPostData{MimeType: contentType, Text: text, Comment: comment}
+		}
+	}
+
+	return captured
+}
+
+func (m *bodyCaptureMiddleware) captureResponse(ctx contractshttp.Context, c fiber.Ctx) harResponse {
+	response := c.Response()
+	status := response.StatusCode()
+	contentType := string(response.Header.ContentType())
+
+	captured := harResponse{
+		Status:      status,
+		StatusText:  http.StatusText(status),
+		HTTPVersion: c.Protocol(),
+		Cookies:     []harNameValue{},
+		Headers:     m.captureHeaders(response.Header.All()),
+		Content:     harContent{MimeType: contentType},
+		RedirectURL: string(response.Header.Peek(fiber.HeaderLocation)),
+		HeadersSize: -1,
+	}
+
+	// Reading the body of a stream would consume it, its size is only known from Content-Length.
+	if response.IsBodyStream() {
+		captured.BodySize = response.Header.ContentLength()
+		captured.Content.Size = captured.BodySize
+		captured.Content.Comment = "streamed body omitted"
+		return captured
+	}
+
+	body := ctx.Response().Origin().Body().Bytes()
+	captured.BodySize = len(body)
+	captured.Content.Size = len(body)
+	captured.Content.Text, captured.Content.Comment = m.captureBody(body, contentType)
+
+	return captured
+}
+
+// captureBody redacts the JSON and the form bodies and cuts the body off at MaxBodySize, the comment tells why
+// the text isn't the complete body. The other bodies and the ones failing to parse are omitted, since the
+// secrets in them can't be redacted, except the text bodies if CaptureText is enabled.
+func (m *bodyCaptureMiddleware) captureBody(body []byte, contentType string) (string, string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json"):
+		redacted, err := m.redactJSON(body)
+		if err != nil {
+			return unredactableBody, ""
+		}
+
+		return m.truncate(redacted)
+	case mediaType == fiber.MIMEApplicationForm:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return unredactableBody, ""
+		}
+		m.redactValues(values)
+
+		return m.truncate([]byte(values.Encode()))
+	case m.config.CaptureText && isTextMediaType(mediaType) && utf8.Valid(body):
+		return m.truncate(body)
+	default:
+		return unredactableBody, ""
+	}
+}
+
+// isTextMediaType reports whether the media type is text, e.g. text/plain, text/html or application/xml.
+func isTextMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") || mediaType == fiber.MIMEApplicationXML || strings.HasSuffix(mediaType, "+xml")
+}
+
+func (m *bodyCaptureMiddleware) truncate(body []byte) (string, string) {
+	if len(body) <= m.config.MaxBodySize {
+		return string(body), ""
+	}
+
+	// The body is cut off at the start of a rune, so the text stays valid UTF-8.
+	end := m.config.MaxBodySize
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+
+	return string(body[:end]), fmt.Sprintf("truncated from %d bytes", len(body))
+}
+
+func (m *bodyCaptureMiddleware) captureHeaders(headers iter.Seq2[[]byte, []byte]) []harNameValue {
+	captured := []harNameValue{}
+	for key, value := range headers {
+		name := string(key)
+		item := harNameValue{Name: name, Value: string(value)}
+		if m.redactHeaders[strings.ToLower(name)] {
+			item.Value = redactedValue
+		}
+		captured = append(captured, item)
+	}
+
+	return captured
+}
+
+// redactJSON redacts the keys of the objects at any depth, the other values are kept as they are, e.g. the IDs
+// larger than the float precision.
+func (m *bodyCaptureMiddleware) redactJSON(body []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(body)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var object map[string]rawJSONValue
+		if err := json.Unmarshal(trimmed, &object); err != nil {
+			return nil, err
+		}
+		for key, item := range object {
+			if m.redactKeys[strings.ToLower(key)] {
+				object[key] = rawJSONValue(`"` + redactedValue + `"`)
+				continue
+			}
+			redacted, err := m.redactJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = redacted
+		}
+
+		return json.Marshal(object)
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var array []rawJSONValue
+		if err := json.Unmarshal(trimmed, &array); err != nil {
+			return nil, err
+		}
+		for i, item := range array {
+			redacted, err := m.redactJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = redacted
+		}
+
+		return json.Marshal(array)
+	default:
+		var value rawJSONValue
+		if err := json.Unmarshal(trimmed, &value); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	}
+}
+
+// rawJSONValue keeps a JSON value as it is while the enclosing object or array is decoded and encoded again.
+type rawJSONValue []byte
+
+func (v rawJSONValue) MarshalJSON() ([]byte, error) {
+	return v, nil
+}
+
+func (v *rawJSONValue) UnmarshalJSON(data []byte) error {
+	*v = slices.Clone(data)
+
+	return nil
+}
+
+func (m *bodyCaptureMiddleware) redactValues(values url.Values) {
+	for key, items := range values {
+		if m.redactKeys[strings.ToLower(key)] {
+			for i := range items {
+				items[i] = redactedValue
+			}
+		}
+	}
+}
+
+func (m *bodyCaptureMiddleware) writeHAR(ctx contractshttp.Context, entry harEntry) error {
+	if err := os.MkdirAll(m.config.Directory, 0o700); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(harLog{Log: harLogContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "goravel/fiber", Version: "1.0"},
+		Entries: []harEntry{entry},
+	}})
+	if err != nil {
+		return err
+	}
+
+	id := GetRequestID(ctx)
+	if id == "" {
+		id = fmt.Sprintf("%08x", rand.Uint32())
+	}
+	name := fmt.Sprintf("%s-%s.har", time.Now().UTC().Format("20060102T150405.000000000"), filepath.Base(id))
+
+	// The payloads are sensitive despite the redaction, so only the owner can read them.
+	return os.WriteFile(filepath.Join(m.config.Directory, name), content, 0o600)
+}
+
+// BodyCapture creates middleware to capture the request and the response bodies for debugging, the
+// configured keys and headers are redacted. The captures are logged via LogFacade or written as HAR files,
+// add the middleware to the routes under investigation and register it after Compress to capture the
+// uncompressed responses. It panics if the sample rate isn't between 0 and 1.
+func BodyCapture(config ...BodyCaptureConfig) contractshttp.Middleware {
+	var cfg BodyCaptureConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		panic(fmt.Sprintf("body capture sample rate %v isn't between 0 and 1", cfg.SampleRate))
+	}
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 1
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = 64 * 1024
+	}
+
+	middleware := &bodyCaptureMiddleware{
+		config:        cfg,
+		redactKeys:    make(map[string]bool),
+		redactHeaders: make(map[string]bool),
+	}
+	for _, key := range slices.Concat(defaultBodyCaptureRedactKeys, cfg.RedactKeys) {
+		middleware.redactKeys[strings.ToLower(key)] = true
+	}
+	for _, header := range slices.Concat(defaultBodyCaptureRedactHeaders, cfg.RedactHeaders) {
+		middleware.redactHeaders[strings.ToLower(header)] = true
+	}
+
+	return middleware
+}
+
+// The HAR 1.2 format, see http://www.softwareishard.com/blog/har-12-spec/.
+type harLog struct {
+	Log harLogContent `json:"log"`
+}
+
+type harLogContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func harNameValues(values url.Values) []harNameValue {
+	result := []harNameValue{}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		for _, value := range values[key] {
+			result = append(result, harNameValue{Name: key, Value: value})
+		}
+	}
+
+	return result
+}
+
+// harHeadersMap joins the values of the repeated headers for the log entries.
+func harHeadersMap(headers []harNameValue) map[string]string {
+	result := make(map[string]string, len(headers))
+	for _, header := range headers {
+		if value, ok := result[header.Name]; ok {
+			result[header.Name] = value + ", " + header.Value
+		} else {
+			result[header.Name] = header.Value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,266 @@
+package fiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyCapture(t *testing.T) {
+	defer func() {
+		bodyCaptureSample = rand.Float64
+	}()
+
+	echo := func(ctx contractshttp.Context) contractshttp.Response {
+		c := ctx.(*Context).Instance()
+		c.Set(fiber.HeaderSetCookie, "session=abc")
+		return ctx.Response().Data(http.StatusCreated, string(c.Request().Header.ContentType()), c.Body())
+	}
+
+	tests := []struct {
+		name      string
+		config    BodyCaptureConfig
+		sample    float64
+		path      string
+		body      func() (io.Reader, string)
+		handler   contractshttp.HandlerFunc
+		expectLog bool
+		// expectError is the error logged by the context request failing to parse the body.
+		expectError bool
+		expectData  map[string]any
+		expectNotes map[string]string
+	}{
+		{
+			name:   "redact JSON",
+			config: BodyCaptureConfig{RedactKeys: []string{"SSN"}},
+			path:   "/users?token=abc&page=1",
+			body: func() (io.Reader, string) {
+				return strings.NewReader(`{"name":"goravel","password":"secret","profile":{"ssn":"123"},"ids":[9007199254740993],"cards":[{"token":"t"}]}`), fiber.MIMEApplicationJSON
+			},
+			handler:   echo,
+			expectLog: true,
+			expectData: map[string]any{
+				"method":        "POST",
+				"url":           "http://example.com/users?page=1&token=%5BREDACTED%5D",
+				"status":        http.StatusCreated,
+				"request_body":  `{"cards":[{"token":"[REDACTED]"}],"ids":[9007199254740993],"name":"goravel","password":"[REDACTED]","profile":{"ssn":"[REDACTED]"}}`,
+				"response_body": `{"cards":[{"token":"[REDACTED]"}],"ids":[9007199254740993],"name":"goravel","password":"[REDACTED]","profile":{"ssn":"[REDACTED]"}}`,
+			},
+		},
+		{
+			name: "redact form",
+			body: func() (io.Reader, string) {
+				return strings.NewReader("name=goravel&password=secret"), fiber.MIMEApplicationForm
+			},
+			expectLog: true,
+			expectData: map[string]any{
+				"request_body":  "name=goravel&password=%5BREDACTED%5D",
+				"response_body": unredactableBody,
+			},
+		},
+		{
+			name: "multipart form",
+			body: func() (io.Reader, string) {
+				var body bytes.Buffer
+				writer := multipart.NewWriter(&body)
+				require.NoError(t, writer.WriteField("password", "secret"))
+				file, err := writer.CreateFormFile("avatar", "avatar.png")
+				require.NoError(t, err)
+				_, err = file.Write([]byte{0x89, 'P', 'N', 'G'})
+				require.NoError(t, err)
+				require.NoError(t, writer.Close())
+				return &body, writer.FormDataContentType()
+			},
+			expectLog:  true,
+			expectData: map[string]any{"request_body": "avatar=%40avatar.png&password=%5BREDACTED%5D"},
+		},
+		{
+			name:   "truncate",
+			config: BodyCaptureConfig{MaxBodySize: 11},
+			body: func() (io.Reader, string) {
+				return strings.NewReader(`{"name":"héllo"}`), fiber.MIMEApplicationJSON
+			},
+			expectLog:   true,
+			expectData:  map[string]any{"request_body": `{"name":"h`},
+			expectNotes: map[string]string{"request_body_comment": "truncated from 17 bytes"},
+		},
+		{
+			name: "malformed JSON",
+			body: func() (io.Reader, string) {
+				return strings.NewReader(`{"password":"secret"`), fiber.MIMEApplicationJSON
+			},
+			expectLog:   true,
+			expectError: true,
+			expectData:  map[string]any{"request_body": unredactableBody},
+		},
+		{
+			name: "JSON under another content type",
+			body: func() (io.Reader, string) {
+				return strings.NewReader(`{"password":"secret"}`), fiber.MIMETextPlain
+			},
+			expectLog:  true,
+			expectData: map[string]any{"request_body": unredactableBody},
+		},
+		{
+			name:   "capture text",
+			config: BodyCaptureConfig{CaptureText: true, MaxBodySize: 16},
+			body: func() (io.Reader, string) {
+				return strings.NewReader(`<user><password>secret</password></user>`), fiber.MIMEApplicationXMLCharsetUTF8
+			},
+			handler: func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().Data(http.StatusOK, fiber.MIMETextHTMLCharsetUTF8, []byte("<p>goravel</p>"))
+			},
+			expectLog: true,
+			expectData: map[string]any{
+				"request_body":  `<user><password>`,
+				"response_body": "<p>goravel</p>",
+			},
+			expectNotes: map[string]string{"request_body_comment": "truncated from 40 bytes"},
+		},
+		{
+			name:   "binary isn't captured as text",
+			config: BodyCaptureConfig{CaptureText: true},
+			handler: func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().Data(http.StatusOK, fiber.MIMETextPlain, []byte{0x89, 0xff, 0xfe})
+			},
+			expectLog:  true,
+			expectData: map[string]any{"response_body": unredactableBody},
+		},
+		{
+			name: "binary",
+			handler: func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().Data(http.StatusOK, "image/png", []byte{0x89, 0xff, 0xfe})
+			},
+			expectLog:  true,
+			expectData: map[string]any{"response_body": unredactableBody},
+		},
+		{
+			name:   "sampled out",
+			config: BodyCaptureConfig{SampleRate: 0.5},
+			sample: 0.5,
+		},
+		{
+			name:      "sampled in",
+			config:    BodyCaptureConfig{SampleRate: 0.5},
+			sample:    0.4,
+			expectLog: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bodyCaptureSample = func() float64 {
+				return test.sample
+			}
+
+			var data map[string]any
+			mockLog := mockslog.NewLog(t)
+			mockWriter := mockslog.NewWriter(t)
+			LogFacade = mockLog
+			if test.expectLog {
+				mockLog.EXPECT().WithContext(mock.Anything).Return(mockLog).Once()
+				mockLog.EXPECT().With(mock.Anything).Run(func(args map[string]any) {
+					data = args
+				}).Return(mockWriter).Once()
+				mockWriter.EXPECT().Info(mock.AnythingOfType("string")).Once()
+			}
+			if test.expectError {
+				mockLog.EXPECT().Error(mock.Anything).Once()
+			}
+
+			app := newMiddlewareTestApp(test.handler, BodyCapture(test.config))
+
+			path := test.path
+			if path == "" {
+				path = "/"
+			}
+			var (
+				body        io.Reader
+				contentType string
+			)
+			if test.body != nil {
+				body, contentType = test.body()
+			}
+			req := httptest.NewRequest("POST", path, body)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+			if contentType != "" {
+				req.Header.Set(fiber.HeaderContentType, contentType)
+			}
+			_, err := app.Test(req)
+			require.NoError(t, err)
+
+			if !test.expectLog {
+				return
+			}
+			for key, value := range test.expectData {
+				assert.Equal(t, value, data[key], key)
+			}
+			for key, value := range test.expectNotes {
+				assert.Equal(t, value, data[key], key)
+			}
+			assert.Equal(t, redactedValue, data["request_headers"].(map[string]string)[fiber.HeaderAuthorization])
+			if cookie, ok := data["response_headers"].(map[string]string)[fiber.HeaderSetCookie]; ok {
+				assert.Equal(t, redactedValue, cookie)
+			}
+		})
+	}
+
+	assert.Panics(t, func() {
+		BodyCapture(BodyCaptureConfig{SampleRate: 2})
+	})
+}
+
+func TestBodyCaptureHAR(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "captures")
+	app := newMiddlewareTestApp(nil, RequestID(RequestIDConfig{Generator: func() string {
+		return "generated"
+	}}), BodyCapture(BodyCaptureConfig{Directory: directory}))
+
+	req := httptest.NewRequest("PUT", "/users/1?api_key=key", strings.NewReader(`{"password":"secret"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderCookie, "session=abc")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(directory, "*-generated.har"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	var har harLog
+	require.NoError(t, json.Unmarshal(content, &har))
+
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 1)
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "PUT", entry.Request.Method)
+	assert.Equal(t, "http://example.com/users/1?api_key=%5BREDACTED%5D", entry.Request.URL)
+	assert.Equal(t, "HTTP/1.1", entry.Request.HTTPVersion)
+	assert.Equal(t, []harNameValue{{Name: "api_key", Value: redactedValue}}, entry.Request.QueryString)
+	assert.Contains(t, entry.Request.Headers, harNameValue{Name: fiber.HeaderCookie, Value: redactedValue})
+	assert.Equal(t, &harPostData{MimeType: fiber.MIMEApplicationJSON, Text: `{"password":"[REDACTED]"}`}, entry.Request.PostData)
+	assert.Equal(t, 21, entry.Request.BodySize)
+	assert.Equal(t, http.StatusOK, entry.Response.Status)
+	assert.Equal(t, "OK", entry.Response.StatusText)
+	assert.Equal(t, harContent{Size: 2, MimeType: fiber.MIMETextPlainCharsetUTF8, Text: unredactableBody}, entry.Response.Content)
+	assert.Equal(t, "goravel:body_capture", BodyCapture().Signature())
+}
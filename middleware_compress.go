@@ -0,0 +1,269 @@
+package fiber
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	CompressLevelDefault = iota
+	CompressLevelBestSpeed
+	CompressLevelBestCompression
+)
+
+// CompressConfig configures the Compress middleware, the zero value uses the defaults.
+type CompressConfig struct {
+	// Level is CompressLevelDefault, CompressLevelBestSpeed or CompressLevelBestCompression.
+	Level int
+	// MinLength is the minimum body size in bytes to compress, streamed bodies are always compressed.
+	// Default: 1024
+	MinLength int
+	// ContentTypes are the compressible content types, an entry ending with "/" matches a whole type, e.g. "text/".
+	// Default: text/, JSON, JavaScript, XML, SVG and WebAssembly.
+	ContentTypes []string
+	// Encodings are the supported encodings in the order of the server preference, it's used when the client
+	// accepts several encodings with the same quality.
+	// Default: zstd, br, gzip, deflate.
+	Encodings []string
+}
+
+var defaultCompressContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/javascript",
+	"application/x-javascript",
+	"application/xml",
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/problem+json",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+var defaultCompressEncodings = []string{"zstd", "br", "gzip", "deflate"}
+
+type compressMiddleware struct {
+	config CompressConfig
+}
+
+func (m *compressMiddleware) Signature() string {
+	return "goravel:compress"
+}
+
+func (m *compressMiddleware) Handle(ctx contractshttp.Context) {
+	ctx.Request().Next()
+
+	c := ctx.(*Context).Instance()
+	if invalidFiber(c) || !shouldCompress(c) {
+		return
+	}
+
+	// The response depends on Accept-Encoding even when it isn't compressed, so caches must key on it.
+	appendVary(c, fiber.HeaderAcceptEncoding)
+
+	if c.GetRespHeader(fiber.HeaderContentEncoding) != "" || !m.compressible(c) {
+		return
+	}
+
+	encoding := negotiateEncoding(c.Get(fiber.HeaderAcceptEncoding), m.config.Encodings)
+	if encoding == "" {
+		return
+	}
+
+	response := c.Response()
+	if response.IsBodyStream() {
+		compressBodyStream(c, encoding, m.config.Level)
+		if len(response.Header.ContentEncoding()) == 0 {
+			return
+		}
+	} else {
+		body := response.Body()
+		if len(body) < m.config.MinLength {
+			return
+		}
+		response.SetBodyRaw(compressBytes(body, encoding, m.config.Level))
+		response.Header.Set(fiber.HeaderContentEncoding, encoding)
+	}
+
+	// A strong ETag identifies the identity body, it must not be reused for the encoded one.
+	if etag := c.GetRespHeader(fiber.HeaderETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+		c.Set(fiber.HeaderETag, "W/"+etag)
+	}
+}
+
+func (m *compressMiddleware) compressible(c fiber.Ctx) bool {
+	contentType := strings.ToLower(string(c.Response().Header.ContentType()))
+	if index := strings.IndexByte(contentType, ';'); index >= 0 {
+		contentType = contentType[:index]
+	}
+	contentType = strings.TrimSpace(contentType)
+
+	for _, allowed := range m.config.ContentTypes {
+		if strings.HasSuffix(allowed, "/") {
+			if strings.HasPrefix(contentType, allowed) {
+				return true
+			}
+		} else if contentType == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Compress creates middleware to compress responses with zstd, brotli, gzip or deflate negotiated from
+// Accept-Encoding. It can be disabled for a route via WithoutMiddleware(Compress()).
+func Compress(config ...CompressConfig) contractshttp.Middleware {
+	var cfg CompressConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Level < CompressLevelDefault || cfg.Level > CompressLevelBestCompression {
+		cfg.Level = CompressLevelDefault
+	}
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = 1024
+	}
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = defaultCompressContentTypes
+	}
+	if len(cfg.Encodings) == 0 {
+		cfg.Encodings = defaultCompressEncodings
+	}
+
+	return &compressMiddleware{config: cfg}
+}
+
+func shouldCompress(c fiber.Ctx) bool {
+	if c.Method() == fiber.MethodHead || c.Get(fiber.HeaderRange) != "" {
+		return false
+	}
+
+	status := c.Response().StatusCode()
+	if status < fiber.StatusOK || status == fiber.StatusNoContent || status == fiber.StatusResetContent ||
+		status == fiber.StatusPartialContent || status == fiber.StatusNotModified {
+		return false
+	}
+
+	for _, cacheControl := range []string{c.Get(fiber.HeaderCacheControl), c.GetRespHeader(fiber.HeaderCacheControl)} {
+		if hasHeaderToken(cacheControl, "no-transform") {
+			return false
+		}
+	}
+
+	return c.Response().IsBodyStream() || len(c.Response().Body()) > 0
+}
+
+// negotiateEncoding picks the encoding with the highest quality in Accept-Encoding,
+// ties are resolved by the order of the supported encodings.
+func negotiateEncoding(acceptEncoding string, supported []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	wildcard := -1.0
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+
+		if name == "*" {
+			wildcard = quality
+		} else {
+			qualities[name] = quality
+		}
+	}
+
+	var (
+		best        string
+		bestQuality float64
+	)
+	for _, encoding := range supported {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = wildcard
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+
+	return best
+}
+
+func compressBytes(body []byte, encoding string, level int) []byte {
+	level = encoderLevel(encoding, level)
+	switch encoding {
+	case "zstd":
+		return fasthttp.AppendZstdBytesLevel(nil, body, level)
+	case "br":
+		return fasthttp.AppendBrotliBytesLevel(nil, body, level)
+	case "gzip":
+		return fasthttp.AppendGzipBytesLevel(nil, body, level)
+	default:
+		return fasthttp.AppendDeflateBytesLevel(nil, body, level)
+	}
+}
+
+// encoderLevel maps the CompressLevel to the level of the encoder, each encoder has its own scale.
+func encoderLevel(encoding string, level int) int {
+	switch encoding {
+	case "zstd":
+		return []int{fasthttp.CompressZstdDefault, fasthttp.CompressZstdBestSpeed, fasthttp.CompressZstdBestCompression}[level]
+	case "br":
+		return []int{fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBrotliBestCompression}[level]
+	default:
+		return []int{fasthttp.CompressDefaultCompression, fasthttp.CompressBestSpeed, fasthttp.CompressBestCompression}[level]
+	}
+}
+
+// compressBodyStream wraps the body stream with the encoder of fasthttp, it's the only way to replace the stream
+// without closing it. The stream is compressed chunk by chunk, so the chunks flushed by a StreamResponse reach the
+// client immediately. fasthttp picks the encoder from Accept-Encoding, so the header is narrowed to the negotiated
+// encoding and the level of that encoder is passed. fasthttp also skips the content types it doesn't consider
+// compressible, the ContentTypes of the config are checked already, so it sees a compressible one meanwhile.
+func compressBodyStream(c fiber.Ctx, encoding string, level int) {
+	header := &c.Request().Header
+	acceptEncoding := append([]byte(nil), header.Peek(fiber.HeaderAcceptEncoding)...)
+	header.Set(fiber.HeaderAcceptEncoding, encoding)
+	defer header.SetBytesV(fiber.HeaderAcceptEncoding, acceptEncoding)
+
+	responseHeader := &c.Response().Header
+	contentType := append([]byte(nil), responseHeader.ContentType()...)
+	responseHeader.SetContentType(fiber.MIMEOctetStream)
+	defer responseHeader.SetContentTypeBytes(contentType)
+
+	level = encoderLevel(encoding, level)
+	fasthttp.CompressHandlerBrotliLevel(func(*fasthttp.RequestCtx) {}, level, level)(c.RequestCtx())
+}
+
+func appendVary(c fiber.Ctx, header string) {
+	vary := c.GetRespHeader(fiber.HeaderVary)
+	if vary == "" {
+		c.Set(fiber.HeaderVary, header)
+		return
+	}
+	if hasHeaderToken(vary, "*") || hasHeaderToken(vary, header) {
+		return
+	}
+	c.Set(fiber.HeaderVary, vary+", "+header)
+}
+
+func hasHeaderToken(header, token string) bool {
+	return slices.ContainsFunc(strings.Split(header, ","), func(part string) bool {
+		return strings.EqualFold(strings.TrimSpace(part), token)
+	})
+}
@@ -0,0 +1,279 @@
+package fiber
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/static"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	largeJson := `{"name":"` + strings.Repeat("goravel", 300) + `"}`
+
+	jsonHandler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Data(http.StatusOK, "application/json", []byte(largeJson))
+	}
+
+	tests := []struct {
+		name           string
+		config         CompressConfig
+		handler        contractshttp.HandlerFunc
+		acceptEncoding string
+		expectEncoding string
+		expectBody     string
+	}{
+		{
+			name:           "prefer the server order when qualities are equal",
+			handler:        jsonHandler,
+			acceptEncoding: "gzip, deflate, br, zstd",
+			expectEncoding: "zstd",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "respect the quality",
+			handler:        jsonHandler,
+			acceptEncoding: "br;q=0.5, gzip",
+			expectEncoding: "gzip",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "brotli",
+			handler:        jsonHandler,
+			acceptEncoding: "br",
+			expectEncoding: "br",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "deflate",
+			handler:        jsonHandler,
+			acceptEncoding: "deflate",
+			expectEncoding: "deflate",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "custom encodings",
+			config:         CompressConfig{Encodings: []string{"gzip"}},
+			handler:        jsonHandler,
+			acceptEncoding: "br, gzip",
+			expectEncoding: "gzip",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "no accepted encoding",
+			handler:        jsonHandler,
+			acceptEncoding: "identity",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "body smaller than the minimum length",
+			handler:        nil,
+			acceptEncoding: "gzip",
+			expectBody:     "ok",
+		},
+		{
+			name: "content type not allowed",
+			handler: func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().Data(http.StatusOK, "image/png", []byte(largeJson))
+			},
+			acceptEncoding: "gzip",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "custom content types",
+			config:         CompressConfig{ContentTypes: []string{"image/"}},
+			handler:        jsonHandler,
+			acceptEncoding: "gzip",
+			expectBody:     largeJson,
+		},
+		{
+			name:           "stream response",
+			handler:        streamHandler("text/event-stream"),
+			acceptEncoding: "gzip",
+			expectEncoding: "gzip",
+			expectBody:     strings.Repeat("data: goravel\n\n", 3),
+		},
+		{
+			name:           "stream response with zstd",
+			config:         CompressConfig{Level: CompressLevelBestCompression},
+			handler:        streamHandler("text/event-stream"),
+			acceptEncoding: "zstd",
+			expectEncoding: "zstd",
+			expectBody:     strings.Repeat("data: goravel\n\n", 3),
+		},
+		{
+			name:           "stream response of custom content types",
+			config:         CompressConfig{ContentTypes: []string{"image/"}},
+			handler:        streamHandler("image/bmp"),
+			acceptEncoding: "br",
+			expectEncoding: "br",
+			expectBody:     strings.Repeat("data: goravel\n\n", 3),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newMiddlewareTestApp(test.handler, Compress(test.config))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, test.expectEncoding, resp.Header.Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", resp.Header.Get("Vary"))
+			assert.Equal(t, test.expectBody, decompress(t, resp.Header.Get("Content-Encoding"), resp.Body))
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{"zstd", "br", "gzip", "deflate"}
+
+	assert.Equal(t, "", negotiateEncoding("", supported))
+	assert.Equal(t, "gzip", negotiateEncoding("gzip", supported))
+	assert.Equal(t, "br", negotiateEncoding("gzip, br", supported))
+	assert.Equal(t, "gzip", negotiateEncoding("gzip;q=1.0, br;q=0.8", supported))
+	assert.Equal(t, "", negotiateEncoding("gzip;q=0", supported))
+	assert.Equal(t, "zstd", negotiateEncoding("*", supported))
+	assert.Equal(t, "br", negotiateEncoding("*;q=0.1, br, zstd;q=0", supported))
+	assert.Equal(t, "deflate", negotiateEncoding("DEFLATE", supported))
+}
+
+func TestPrecompressedStatic(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.js"), []byte("console.log('goravel')"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.js.br"), []byte("brotli"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "LICENSE"), []byte("MIT"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "LICENSE.br"), []byte("brotli"), 0644))
+
+	app := fiber.New()
+	app.Use("/assets", precompressedStatic("/assets", os.DirFS(root)), static.New(root, static.Config{ByteRange: true}))
+
+	tests := []struct {
+		name              string
+		path              string
+		acceptEncoding    string
+		rangeHeader       string
+		expectStatus      int
+		expectEncoding    string
+		expectContentType string
+		expectBody        string
+	}{
+		{
+			name:              "serve the precompressed sibling",
+			acceptEncoding:    "gzip, br",
+			expectEncoding:    "br",
+			expectContentType: "javascript",
+			expectBody:        "brotli",
+		},
+		{
+			name:              "fall through without the sibling",
+			acceptEncoding:    "gzip",
+			expectContentType: "javascript",
+			expectBody:        "console.log('goravel')",
+		},
+		{
+			name:              "fall through for a range request",
+			acceptEncoding:    "br",
+			rangeHeader:       "bytes=0-6",
+			expectStatus:      http.StatusPartialContent,
+			expectContentType: "javascript",
+			expectBody:        "console",
+		},
+		{
+			name:           "fall through for a file without an extension",
+			path:           "/assets/LICENSE",
+			acceptEncoding: "br",
+			expectBody:     "MIT",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, status := test.path, test.expectStatus
+			if path == "" {
+				path = "/assets/app.js"
+			}
+			if status == 0 {
+				status = http.StatusOK
+			}
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+			if test.rangeHeader != "" {
+				req.Header.Set("Range", test.rangeHeader)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, status, resp.StatusCode)
+			assert.Equal(t, test.expectEncoding, resp.Header.Get("Content-Encoding"))
+			assert.Contains(t, resp.Header.Get("Content-Type"), test.expectContentType)
+			assert.Equal(t, test.expectBody, string(body))
+		})
+	}
+}
+
+func streamHandler(contentType string) contractshttp.HandlerFunc {
+	return func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.Response().Header("Content-Type", contentType)
+		return ctx.Response().Stream(http.StatusOK, func(w contractshttp.StreamWriter) error {
+			for i := 0; i < 3; i++ {
+				if _, err := w.WriteString("data: goravel\n\n"); err != nil {
+					return err
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+func decompress(t *testing.T, encoding string, body io.Reader) string {
+	var (
+		reader io.Reader
+		err    error
+	)
+	switch encoding {
+	case "zstd":
+		var decoder *zstd.Decoder
+		decoder, err = zstd.NewReader(body)
+		require.NoError(t, err)
+		defer decoder.Close()
+		reader = decoder
+	case "br":
+		reader = brotli.NewReader(body)
+	case "gzip":
+		reader, err = gzip.NewReader(body)
+		require.NoError(t, err)
+	case "deflate":
+		reader, err = zlib.NewReader(body)
+		require.NoError(t, err)
+	default:
+		reader = body
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, reader)
+	require.NoError(t, err)
+
+	return buf.String()
+}
@@ -0,0 +1,215 @@
+package fiber
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+// ConcurrencyPriority orders the queued requests of the Concurrency middleware, the higher one is served first.
+type ConcurrencyPriority int
+
+const (
+	ConcurrencyPriorityLow ConcurrencyPriority = iota - 1
+	ConcurrencyPriorityNormal
+	ConcurrencyPriorityHigh
+	// ConcurrencyPriorityCritical requests are never queued or shed, e.g. the health checks.
+	ConcurrencyPriorityCritical
+)
+
+// ConcurrencyConfig configures the Concurrency middleware, the zero value uses the defaults.
+type ConcurrencyConfig struct {
+	// Max is the number of requests served at the same time.
+	// Default: 100
+	Max int
+	// Queue is the number of requests waiting for a slot, the others are shed. A full queue makes room for
+	// a request by shedding a waiter with a lower priority.
+	// Default: 0, requests over Max are shed immediately.
+	Queue int
+	// MaxWait is how long a request waits in the queue before it's shed.
+	// Default: 1 second
+	MaxWait time.Duration
+	// RetryAfter is sent in the Retry-After header of the shed requests.
+	// Default: 1 second
+	RetryAfter time.Duration
+	// Priorities maps path patterns to priorities, matched via path.Match without the leading and trailing
+	// slashes, e.g. {"health": ConcurrencyPriorityCritical, "admin/*": ConcurrencyPriorityCritical}.
+	// The highest priority of the matched patterns is used, the others are ConcurrencyPriorityNormal.
+	Priorities map[string]ConcurrencyPriority
+	// Priority resolves the priority of a request, it takes precedence over Priorities.
+	Priority func(ctx contractshttp.Context) ConcurrencyPriority
+	// Response renders the response of the shed requests.
+	// Default: abort with 503 Service Unavailable.
+	Response func(ctx contractshttp.Context)
+}
+
+type concurrencyWaiter struct {
+	priority ConcurrencyPriority
+	// ready receives true when the slot of a finished request is handed over, false when the waiter is shed.
+	ready chan bool
+}
+
+type concurrencyMiddleware struct {
+	config     ConcurrencyConfig
+	priorities map[string]ConcurrencyPriority
+
+	mu       sync.Mutex
+	inFlight int
+	// waiters are ordered by priority, then by arrival.
+	waiters []*concurrencyWaiter
+}
+
+func (m *concurrencyMiddleware) Signature() string {
+	return "goravel:concurrency"
+}
+
+func (m *concurrencyMiddleware) Handle(ctx contractshttp.Context) {
+	priority := m.priority(ctx)
+	if priority >= ConcurrencyPriorityCritical {
+		ctx.Request().Next()
+		return
+	}
+
+	if !m.acquire(ctx, priority) {
+		// The Timeout middleware has answered the request if its deadline passed while waiting.
+		if ctx.Err() != nil {
+			return
+		}
+
+		ctx.Response().Header(HeaderRetryAfter, strconv.Itoa(int(math.Ceil(m.config.RetryAfter.Seconds()))))
+		if m.config.Response != nil {
+			m.config.Response(ctx)
+		} else {
+			ctx.Request().Abort(contractshttp.StatusServiceUnavailable)
+		}
+		return
+	}
+	defer m.release()
+
+	ctx.Request().Next()
+}
+
+func (m *concurrencyMiddleware) priority(ctx contractshttp.Context) ConcurrencyPriority {
+	if m.config.Priority != nil {
+		return m.config.Priority(ctx)
+	}
+
+	priority, matched := ConcurrencyPriorityNormal, false
+	for pattern, patternPriority := range m.priorities {
+		if matchPathPatterns([]string{pattern}, ctx.Request().Path()) && (!matched || patternPriority > priority) {
+			priority, matched = patternPriority, true
+		}
+	}
+
+	return priority
+}
+
+// acquire takes a slot, it waits in the queue until a slot is handed over, the wait times out, the request is
+// cancelled or a waiter with a higher priority sheds it.
+func (m *concurrencyMiddleware) acquire(ctx contractshttp.Context, priority ConcurrencyPriority) bool {
+	m.mu.Lock()
+	if m.inFlight < m.config.Max {
+		m.inFlight++
+		m.mu.Unlock()
+		return true
+	}
+	if m.config.Queue <= 0 {
+		m.mu.Unlock()
+		return false
+	}
+	if len(m.waiters) >= m.config.Queue {
+		// The last waiter has the lowest priority and arrived the latest.
+		last := m.waiters[len(m.waiters)-1]
+		if last.priority >= priority {
+			m.mu.Unlock()
+			return false
+		}
+		m.waiters = m.waiters[:len(m.waiters)-1]
+		last.ready <- false
+	}
+
+	waiter := &concurrencyWaiter{priority: priority, ready: make(chan bool, 1)}
+	index := slices.IndexFunc(m.waiters, func(w *concurrencyWaiter) bool {
+		return w.priority < priority
+	})
+	if index < 0 {
+		index = len(m.waiters)
+	}
+	m.waiters = slices.Insert(m.waiters, index, waiter)
+	m.mu.Unlock()
+
+	timer := time.NewTimer(m.config.MaxWait)
+	defer timer.Stop()
+
+	select {
+	case granted := <-waiter.ready:
+		return granted
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	m.mu.Lock()
+	if index := slices.Index(m.waiters, waiter); index >= 0 {
+		m.waiters = slices.Delete(m.waiters, index, index+1)
+		m.mu.Unlock()
+		return false
+	}
+	m.mu.Unlock()
+
+	// The slot was handed over or the waiter was shed at the same time.
+	if granted := <-waiter.ready; granted {
+		if ctx.Err() == nil {
+			return true
+		}
+		m.release()
+	}
+
+	return false
+}
+
+// release hands the slot over to the first waiter, so a queued request can't be overtaken by a new one.
+func (m *concurrencyMiddleware) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.waiters) > 0 {
+		waiter := m.waiters[0]
+		m.waiters = m.waiters[1:]
+		waiter.ready <- true
+		return
+	}
+
+	m.inFlight--
+}
+
+// Concurrency creates middleware to cap the requests served at the same time and shed the excess with
+// 503 Service Unavailable, so the application degrades instead of running out of memory under spikes.
+// Every call creates a separate limit, so it can be registered globally or for a route group.
+// NOTICE: Register it after the Timeout middleware, so the time spent in the queue counts towards the timeout
+// and the slot of a timed-out request is kept until its handler returns.
+func Concurrency(config ...ConcurrencyConfig) contractshttp.Middleware {
+	var cfg ConcurrencyConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = 100
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = time.Second
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = time.Second
+	}
+
+	priorities := make(map[string]ConcurrencyPriority, len(cfg.Priorities))
+	for pattern, priority := range cfg.Priorities {
+		priorities[trimPathPatterns([]string{pattern})[0]] = priority
+	}
+
+	return &concurrencyMiddleware{config: cfg, priorities: priorities}
+}
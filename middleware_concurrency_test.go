@@ -0,0 +1,195 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrency(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ConcurrencyConfig
+		// run sends the requests while the first request holds the only slot, release lets the first request finish.
+		run func(t *testing.T, send func(path string) chan *http.Response, middleware *concurrencyMiddleware, release func())
+	}{
+		{
+			name:   "shed without a queue",
+			config: ConcurrencyConfig{Max: 1, RetryAfter: 2 * time.Second},
+			run: func(t *testing.T, send func(string) chan *http.Response, middleware *concurrencyMiddleware, release func()) {
+				resp := <-send("/")
+				assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+				assert.Equal(t, "2", resp.Header.Get(HeaderRetryAfter))
+				release()
+			},
+		},
+		{
+			name:   "serve the queued request when the slot is released",
+			config: ConcurrencyConfig{Max: 1, Queue: 1, MaxWait: time.Minute},
+			run: func(t *testing.T, send func(string) chan *http.Response, middleware *concurrencyMiddleware, release func()) {
+				queued := send("/")
+				waitConcurrencyWaiters(t, middleware, 1)
+
+				assert.Equal(t, http.StatusServiceUnavailable, (<-send("/")).StatusCode)
+
+				release()
+				assert.Equal(t, http.StatusOK, (<-queued).StatusCode)
+			},
+		},
+		{
+			name:   "shed the queued request after the max wait",
+			config: ConcurrencyConfig{Max: 1, Queue: 1, MaxWait: 50 * time.Millisecond},
+			run: func(t *testing.T, send func(string) chan *http.Response, middleware *concurrencyMiddleware, release func()) {
+				resp := <-send("/")
+				assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+				assert.Equal(t, "1", resp.Header.Get(HeaderRetryAfter))
+				release()
+			},
+		},
+		{
+			name: "never shed critical requests",
+			config: ConcurrencyConfig{Max: 1, Priorities: map[string]ConcurrencyPriority{
+				"/health": ConcurrencyPriorityCritical,
+			}},
+			run: func(t *testing.T, send func(string) chan *http.Response, middleware *concurrencyMiddleware, release func()) {
+				assert.Equal(t, http.StatusOK, (<-send("/health")).StatusCode)
+				assert.Equal(t, http.StatusServiceUnavailable, (<-send("/users")).StatusCode)
+				release()
+			},
+		},
+		{
+			name: "a higher priority sheds the lowest queued request",
+			config: ConcurrencyConfig{Max: 1, Queue: 1, MaxWait: time.Minute, Priorities: map[string]ConcurrencyPriority{
+				"admin/*": ConcurrencyPriorityHigh,
+				"reports": ConcurrencyPriorityLow,
+			}},
+			run: func(t *testing.T, send func(string) chan *http.Response, middleware *concurrencyMiddleware, release func()) {
+				normal := send("/users")
+				waitConcurrencyWaiters(t, middleware, 1)
+
+				assert.Equal(t, http.StatusServiceUnavailable, (<-send("/reports")).StatusCode)
+
+				high := send("/admin/users")
+				assert.Equal(t, http.StatusServiceUnavailable, (<-normal).StatusCode)
+
+				release()
+				assert.Equal(t, http.StatusOK, (<-high).StatusCode)
+			},
+		},
+		{
+			name: "custom response",
+			config: ConcurrencyConfig{Max: 1, Response: func(ctx contractshttp.Context) {
+				ctx.Response().String(http.StatusTooManyRequests, "busy").Abort()
+			}},
+			run: func(t *testing.T, send func(string) chan *http.Response, middleware *concurrencyMiddleware, release func()) {
+				resp := <-send("/")
+				assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+				assert.Equal(t, "1", resp.Header.Get(HeaderRetryAfter))
+				release()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			middleware := Concurrency(test.config).(*concurrencyMiddleware)
+			app, send, release := newConcurrencyTestApp(t, middleware)
+
+			test.run(t, send, middleware, release)
+
+			assert.Eventually(t, func() bool {
+				middleware.mu.Lock()
+				defer middleware.mu.Unlock()
+				return middleware.inFlight == 0 && len(middleware.waiters) == 0
+			}, time.Second, 10*time.Millisecond)
+
+			// The slots are released, so the next request is served.
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestConcurrencyWithTimeout(t *testing.T) {
+	middleware := Concurrency(ConcurrencyConfig{Max: 1, Queue: 1, MaxWait: time.Minute}).(*concurrencyMiddleware)
+	_, send, release := newConcurrencyTestApp(t, Timeout(100*time.Millisecond), middleware)
+
+	// The deadline of the Timeout middleware covers the time spent in the queue.
+	resp := <-send("/")
+	assert.Equal(t, http.StatusRequestTimeout, resp.StatusCode)
+
+	// The queued request leaves the queue at the deadline, the first request timed out as well, but it keeps its
+	// slot until the handler returns.
+	waitConcurrencyWaiters(t, middleware, 0)
+	middleware.mu.Lock()
+	assert.Equal(t, 1, middleware.inFlight)
+	middleware.mu.Unlock()
+
+	release()
+	assert.Eventually(t, func() bool {
+		middleware.mu.Lock()
+		defer middleware.mu.Unlock()
+		return middleware.inFlight == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestConcurrencyDefaults(t *testing.T) {
+	middleware := Concurrency().(*concurrencyMiddleware)
+
+	assert.Equal(t, 100, middleware.config.Max)
+	assert.Equal(t, 0, middleware.config.Queue)
+	assert.Equal(t, time.Second, middleware.config.MaxWait)
+	assert.Equal(t, time.Second, middleware.config.RetryAfter)
+	assert.Equal(t, "goravel:concurrency", middleware.Signature())
+}
+
+// newConcurrencyTestApp creates an app whose first request to /block holds its slot until release is called,
+// send serves a request in the background once the first request is in flight.
+func newConcurrencyTestApp(t *testing.T, middlewares ...contractshttp.Middleware) (*fiber.App, func(path string) chan *http.Response, func()) {
+	entered := make(chan struct{})
+	blocked := make(chan struct{})
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		if ctx.Request().Path() == "/block" {
+			close(entered)
+			<-blocked
+		}
+		return ctx.Response().String(http.StatusOK, "ok")
+	}, middlewares...)
+
+	send := func(path string) chan *http.Response {
+		responses := make(chan *http.Response, 1)
+		go func() {
+			resp, err := app.Test(httptest.NewRequest("GET", path, nil), fiber.TestConfig{Timeout: 0})
+			assert.NoError(t, err)
+			responses <- resp
+		}()
+		return responses
+	}
+
+	first := send("/block")
+	<-entered
+
+	release := func() {
+		close(blocked)
+		if resp := <-first; resp != nil {
+			assert.NotEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+		}
+	}
+
+	return app, send, release
+}
+
+func waitConcurrencyWaiters(t *testing.T, middleware *concurrencyMiddleware, count int) {
+	assert.Eventually(t, func() bool {
+		middleware.mu.Lock()
+		defer middleware.mu.Unlock()
+		return len(middleware.waiters) == count
+	}, time.Second, time.Millisecond)
+}
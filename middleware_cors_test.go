@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/gofiber/fiber/v3"
 	contractshttp "github.com/goravel/framework/contracts/http"
 	mocksconfig "github.com/goravel/framework/mocks/config"
 	"github.com/stretchr/testify/assert"
@@ -25,7 +26,23 @@ func TestCors(t *testing.T) {
 		mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
 		mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
 		mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+		mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+		mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+		mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+		mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+		mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+		mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
 		mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
 		mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
 	}
 
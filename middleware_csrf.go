@@ -0,0 +1,138 @@
+package fiber
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+const (
+	HeaderCsrfToken = "X-CSRF-TOKEN"
+	HeaderXsrfToken = "X-XSRF-TOKEN"
+)
+
+// CsrfConfig configures the Csrf middleware, the zero value uses the defaults.
+type CsrfConfig struct {
+	// Except are the paths skipping the verification, they are matched via path.Match without the leading and
+	// trailing slashes, e.g. "webhooks/*".
+	Except []string
+	// Cookie sends the encrypted session token in a cookie readable by JavaScript, so SPAs can send it back
+	// via X-XSRF-TOKEN. It requires the crypt service.
+	Cookie bool
+	// CookieName Default: XSRF-TOKEN
+	CookieName string
+	// CookiePath Default: /
+	CookiePath string
+	// CookieDomain is the domain of the cookie.
+	CookieDomain string
+	// CookieSecure sends the cookie over HTTPS only.
+	CookieSecure bool
+	// CookieSameSite Default: Lax
+	CookieSameSite string
+}
+
+type csrfMiddleware struct {
+	config CsrfConfig
+}
+
+func (m *csrfMiddleware) Signature() string {
+	return "goravel:csrf"
+}
+
+func (m *csrfMiddleware) Handle(ctx contractshttp.Context) {
+	request := ctx.Request()
+	if !isReadingMethod(request.Method()) && !matchPathPatterns(m.config.Except, request.Path()) && !m.tokenMatch(ctx) {
+		request.Abort(contractshttp.StatusTokenMismatch)
+		return
+	}
+
+	request.Next()
+
+	// The token is read after the handler, which may regenerate it, e.g. on login.
+	if m.config.Cookie && request.HasSession() {
+		m.setCookie(ctx, request.Session().Token())
+	}
+}
+
+func (m *csrfMiddleware) tokenMatch(ctx contractshttp.Context) bool {
+	request := ctx.Request()
+	if !request.HasSession() {
+		return false
+	}
+
+	token := request.Input("_token")
+	if token == "" {
+		token = request.Header(HeaderCsrfToken)
+	}
+	if token == "" && m.config.Cookie {
+		if encrypted := request.Header(HeaderXsrfToken); encrypted != "" {
+			decrypted, err := decryptCsrfToken(encrypted)
+			if err != nil {
+				return false
+			}
+			token = decrypted
+		}
+	}
+
+	sessionToken := request.Session().Token()
+
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(sessionToken)) == 1
+}
+
+func (m *csrfMiddleware) setCookie(ctx contractshttp.Context, token string) {
+	if App == nil {
+		panic(errors.New("the application is not set"))
+	}
+
+	encrypted, err := App.MakeCrypt().EncryptString(token)
+	if err != nil {
+		panic(err)
+	}
+
+	ctx.Response().Cookie(contractshttp.Cookie{
+		Name:     m.config.CookieName,
+		Value:    encrypted,
+		Path:     m.config.CookiePath,
+		Domain:   m.config.CookieDomain,
+		Secure:   m.config.CookieSecure,
+		SameSite: m.config.CookieSameSite,
+	})
+}
+
+// Csrf creates middleware to verify the CSRF token of the requests with unsafe methods against the session token,
+// a mismatch is aborted with 419. The token is read from the _token field, X-CSRF-TOKEN or, if Cookie is enabled,
+// the encrypted X-XSRF-TOKEN. It requires the session middleware.
+func Csrf(config ...CsrfConfig) contractshttp.Middleware {
+	var cfg CsrfConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	cfg.Except = trimPathPatterns(cfg.Except)
+
+	if cfg.CookieName == "" {
+		cfg.CookieName = "XSRF-TOKEN"
+	}
+	if cfg.CookiePath == "" {
+		cfg.CookiePath = "/"
+	}
+	if cfg.CookieSameSite == "" {
+		cfg.CookieSameSite = "Lax"
+	}
+
+	return &csrfMiddleware{config: cfg}
+}
+
+func decryptCsrfToken(encrypted string) (string, error) {
+	if App == nil {
+		return "", errors.New("the application is not set")
+	}
+
+	return App.MakeCrypt().DecryptString(encrypted)
+}
+
+func isReadingMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
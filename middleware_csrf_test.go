@@ -0,0 +1,171 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	foundationjson "github.com/goravel/framework/foundation/json"
+	mockscrypt "github.com/goravel/framework/mocks/crypt"
+	mocksfoundation "github.com/goravel/framework/mocks/foundation"
+	"github.com/goravel/framework/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCsrf(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	tests := []struct {
+		name           string
+		config         CsrfConfig
+		withoutSession bool
+		method         string
+		path           string
+		form           url.Values
+		headers        map[string]string
+		setup          func(mockApp *mocksfoundation.Application, mockCrypt *mockscrypt.Crypt)
+		expectStatus   int
+		expectCookie   string
+	}{
+		{
+			name:         "skip the reading methods",
+			method:       "GET",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "missing token",
+			method:       "POST",
+			expectStatus: contractshttp.StatusTokenMismatch,
+		},
+		{
+			name:         "token from the form field",
+			method:       "POST",
+			form:         url.Values{"_token": {"token"}},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "token from the header",
+			method:       "DELETE",
+			headers:      map[string]string{HeaderCsrfToken: "token"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "token mismatch",
+			method:       "PUT",
+			headers:      map[string]string{HeaderCsrfToken: "other"},
+			expectStatus: contractshttp.StatusTokenMismatch,
+		},
+		{
+			name:           "without session",
+			withoutSession: true,
+			method:         "POST",
+			headers:        map[string]string{HeaderCsrfToken: "token"},
+			expectStatus:   contractshttp.StatusTokenMismatch,
+		},
+		{
+			name:         "excluded path",
+			config:       CsrfConfig{Except: []string{"/webhooks/*"}},
+			method:       "POST",
+			path:         "/webhooks/github",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ignore the xsrf header without the cookie variant",
+			method:       "POST",
+			headers:      map[string]string{HeaderXsrfToken: "encrypted"},
+			expectStatus: contractshttp.StatusTokenMismatch,
+		},
+		{
+			name:    "encrypted token from the xsrf header",
+			config:  CsrfConfig{Cookie: true},
+			method:  "PATCH",
+			headers: map[string]string{HeaderXsrfToken: "encrypted"},
+			setup: func(mockApp *mocksfoundation.Application, mockCrypt *mockscrypt.Crypt) {
+				mockApp.EXPECT().MakeCrypt().Return(mockCrypt).Twice()
+				mockCrypt.EXPECT().DecryptString("encrypted").Return("token", nil).Once()
+				mockCrypt.EXPECT().EncryptString("token").Return("encrypted", nil).Once()
+			},
+			expectStatus: http.StatusOK,
+			expectCookie: "XSRF-TOKEN=encrypted; path=/; SameSite=Lax",
+		},
+		{
+			name:    "invalid encrypted token",
+			config:  CsrfConfig{Cookie: true},
+			method:  "POST",
+			headers: map[string]string{HeaderXsrfToken: "invalid"},
+			setup: func(mockApp *mocksfoundation.Application, mockCrypt *mockscrypt.Crypt) {
+				mockApp.EXPECT().MakeCrypt().Return(mockCrypt).Once()
+				mockCrypt.EXPECT().DecryptString("invalid").Return("", assert.AnError).Once()
+			},
+			expectStatus: contractshttp.StatusTokenMismatch,
+		},
+		{
+			name:   "send the cookie on reading methods",
+			config: CsrfConfig{Cookie: true, CookieName: "X-TOKEN", CookieSecure: true, CookieSameSite: "Strict"},
+			method: "GET",
+			setup: func(mockApp *mocksfoundation.Application, mockCrypt *mockscrypt.Crypt) {
+				mockApp.EXPECT().MakeCrypt().Return(mockCrypt).Once()
+				mockCrypt.EXPECT().EncryptString("token").Return("encrypted", nil).Once()
+			},
+			expectStatus: http.StatusOK,
+			expectCookie: "X-TOKEN=encrypted; path=/; secure; SameSite=Strict",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockApp := mocksfoundation.NewApplication(t)
+			mockCrypt := mockscrypt.NewCrypt(t)
+			App = mockApp
+			if test.setup != nil {
+				test.setup(mockApp, mockCrypt)
+			}
+
+			middlewares := []contractshttp.Middleware{Csrf(test.config)}
+			if !test.withoutSession {
+				middlewares = append([]contractshttp.Middleware{&csrfSessionMiddleware{}}, middlewares...)
+			}
+			app := newMiddlewareTestApp(nil, middlewares...)
+
+			target := test.path
+			if target == "" {
+				target = "/"
+			}
+			var body *strings.Reader
+			if test.form != nil {
+				body = strings.NewReader(test.form.Encode())
+			} else {
+				body = strings.NewReader("")
+			}
+			req := httptest.NewRequest(test.method, target, body)
+			if test.form != nil {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+			assert.Equal(t, test.expectCookie, resp.Header.Get("Set-Cookie"))
+		})
+	}
+}
+
+type csrfSessionMiddleware struct{}
+
+func (m *csrfSessionMiddleware) Signature() string { return "test_csrf_session" }
+
+func (m *csrfSessionMiddleware) Handle(ctx contractshttp.Context) {
+	sessionData := session.NewSession("goravel_session", nil, foundationjson.New())
+	sessionData.Put("_token", "token")
+	ctx.Request().SetSession(sessionData)
+	ctx.Request().Next()
+}
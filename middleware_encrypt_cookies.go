@@ -0,0 +1,237 @@
+package fiber
+
+import (
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	contractsconfig "github.com/goravel/framework/contracts/config"
+	contractscrypt "github.com/goravel/framework/contracts/crypt"
+	"github.com/goravel/framework/contracts/foundation"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/crypt"
+	"github.com/spf13/cast"
+	"github.com/valyala/fasthttp"
+)
+
+// defaultEncryptCookiesExcept are the cookies read by JavaScript, the CSRF token is encrypted already.
+var defaultEncryptCookiesExcept = []string{"XSRF-TOKEN"}
+
+// EncryptCookiesConfig configures the EncryptCookies middleware, the zero value uses the defaults.
+type EncryptCookiesConfig struct {
+	// Except are the names of the cookies kept in plain text besides XSRF-TOKEN, e.g. the ones read by JavaScript.
+	Except []string
+	// PreviousKeys are the former app keys, the cookies encrypted with them are still read but the new cookies
+	// are always encrypted with app.key, so the key can be rotated without logging the users out.
+	// Default: app.previous_keys of the config
+	PreviousKeys []string
+}
+
+type encryptCookiesMiddleware struct {
+	except       []string
+	previousKeys []string
+	previousOnce sync.Once
+	previous     map[string]contractscrypt.Crypt
+}
+
+func (m *encryptCookiesMiddleware) Signature() string {
+	return "goravel:encrypt_cookies"
+}
+
+func (m *encryptCookiesMiddleware) Handle(ctx contractshttp.Context) {
+	if App == nil {
+		ctx.Request().Next()
+		return
+	}
+
+	configFacade, crypt := App.MakeConfig(), App.MakeCrypt()
+	if configFacade == nil || crypt == nil {
+		ctx.Request().Next()
+		return
+	}
+
+	appKey := configFacade.GetString("app.key")
+	c := ctx.(*Context).Instance()
+	m.decrypt(&c.Request().Header, crypt, appKey, m.previousCrypts(configFacade))
+
+	ctx.Request().Next()
+
+	if invalidFiber(c) {
+		return
+	}
+	m.encrypt(&c.Response().Header, crypt, appKey)
+}
+
+// previousCrypts creates the crypt services of the previous keys on the first request, the config isn't loaded yet
+// when the middleware is created.
+func (m *encryptCookiesMiddleware) previousCrypts(configFacade contractsconfig.Config) map[string]contractscrypt.Crypt {
+	m.previousOnce.Do(func() {
+		previousKeys := m.previousKeys
+		if previousKeys == nil {
+			previousKeys = cast.ToStringSlice(configFacade.Get("app.previous_keys"))
+		}
+		m.previous = previousCrypts(configFacade, App.Json(), previousKeys)
+	})
+
+	return m.previous
+}
+
+// decrypt replaces the request cookies with their values, the tampered cookies and the ones encrypted with an
+// unknown key are treated as absent.
+func (m *encryptCookiesMiddleware) decrypt(header *fasthttp.RequestHeader, crypt contractscrypt.Crypt, appKey string, previous map[string]contractscrypt.Crypt) {
+	var names, values []string
+	for key, value := range header.Cookies() {
+		names = append(names, string(key))
+		values = append(values, string(value))
+	}
+
+	for i, name := range names {
+		if slices.Contains(m.except, name) {
+			continue
+		}
+
+		if value, ok := decryptCookie(crypt, appKey, previous, name, values[i]); ok {
+			header.SetCookie(name, value)
+		} else {
+			header.DelCookie(name)
+		}
+	}
+}
+
+// encrypt replaces the values of the response cookies with the encrypted ones, the cookies removing a cookie
+// are kept as they are.
+func (m *encryptCookiesMiddleware) encrypt(header *fasthttp.ResponseHeader, crypt contractscrypt.Crypt, appKey string) {
+	var cookies []*fasthttp.Cookie
+	for _, value := range header.Cookies() {
+		cookie := fasthttp.AcquireCookie()
+		if err := cookie.ParseBytes(value); err != nil {
+			fasthttp.ReleaseCookie(cookie)
+			continue
+		}
+		cookies = append(cookies, cookie)
+	}
+
+	for _, cookie := range cookies {
+		name := string(cookie.Key())
+		if len(cookie.Value()) > 0 && !slices.Contains(m.except, name) {
+			encrypted, err := crypt.EncryptString(signCookieValue(appKey, name, string(cookie.Value())))
+			if err != nil {
+				// The cookie is never sent in plain text.
+				header.DelCookie(name)
+				LogFacade.Error(fmt.Errorf("failed to encrypt the cookie %s: %w", name, err))
+			} else {
+				cookie.SetValue(encrypted)
+				header.SetCookie(cookie)
+			}
+		}
+		fasthttp.ReleaseCookie(cookie)
+	}
+}
+
+// EncryptCookies creates middleware to encrypt the response cookies with app.key via the crypt service and to
+// decrypt the request cookies, so ContextRequest.Cookie and ContextResponse.Cookie keep working with the
+// plain values. The values are signed with the cookie name, so a value can't be moved to another cookie.
+// Register it before the middleware reading cookies. It panics if a previous key isn't 16, 24 or 32 bytes.
+func EncryptCookies(config ...EncryptCookiesConfig) contractshttp.Middleware {
+	var cfg EncryptCookiesConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	for _, key := range cfg.PreviousKeys {
+		if _, err := aes.NewCipher([]byte(key)); err != nil {
+			panic(fmt.Sprintf("invalid previous key: %v", err))
+		}
+	}
+
+	return &encryptCookiesMiddleware{
+		except:       slices.Concat(cfg.Except, defaultEncryptCookiesExcept),
+		previousKeys: cfg.PreviousKeys,
+	}
+}
+
+// decryptCookie decrypts the value with app.key via the crypt service, then with the crypt services of the
+// previous keys.
+func decryptCookie(crypt contractscrypt.Crypt, appKey string, previous map[string]contractscrypt.Crypt, name, value string) (string, bool) {
+	if decrypted, err := decryptString(crypt, value); err == nil {
+		if value, ok := verifyCookieValue(appKey, name, decrypted); ok {
+			return value, true
+		}
+	}
+
+	for key, crypt := range previous {
+		decrypted, err := decryptString(crypt, value)
+		if err != nil {
+			continue
+		}
+		if value, ok := verifyCookieValue(key, name, decrypted); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// decryptString decrypts the value via the crypt service, AES-GCM panics on a tampered IV of another length.
+func decryptString(crypt contractscrypt.Crypt, value string) (decrypted string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to decrypt: %v", r)
+		}
+	}()
+
+	return crypt.DecryptString(value)
+}
+
+// previousCrypts creates the crypt services of the previous keys, the invalid keys are skipped.
+func previousCrypts(configFacade contractsconfig.Config, json foundation.Json, previousKeys []string) map[string]contractscrypt.Crypt {
+	crypts := make(map[string]contractscrypt.Crypt, len(previousKeys))
+	for _, key := range previousKeys {
+		aes, err := crypt.NewAES(&keyConfig{Config: configFacade, key: key}, json)
+		if err != nil {
+			continue
+		}
+		crypts[key] = aes
+	}
+
+	return crypts
+}
+
+// keyConfig overrides app.key of the config, so the crypt service is created with another key.
+type keyConfig struct {
+	contractsconfig.Config
+	key string
+}
+
+func (c *keyConfig) GetString(path string, defaultValue ...string) string {
+	if path == "app.key" {
+		return c.key
+	}
+
+	return c.Config.GetString(path, defaultValue...)
+}
+
+func signCookieValue(key, name, value string) string {
+	return cookieNameSignature(key, name) + "|" + value
+}
+
+func verifyCookieValue(key, name, decrypted string) (string, bool) {
+	signature, value, found := strings.Cut(decrypted, "|")
+	if !found || !hmac.Equal([]byte(signature), []byte(cookieNameSignature(key, name))) {
+		return "", false
+	}
+
+	return value, true
+}
+
+func cookieNameSignature(key, name string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(name))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,261 @@
+package fiber
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/crypt"
+	"github.com/goravel/framework/foundation/json"
+	mocksconfig "github.com/goravel/framework/mocks/config"
+	mocksfoundation "github.com/goravel/framework/mocks/foundation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAppKey      = "11111111111111111111111111111111"
+	testPreviousKey = "22222222222222222222222222222222"
+)
+
+func TestEncryptCookies(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	encryptWithKey := func(key, name, value string) string {
+		aes := newTestAES(t, key)
+		encrypted, err := aes.EncryptString(signCookieValue(key, name, value))
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	tests := []struct {
+		name         string
+		config       EncryptCookiesConfig
+		previousKeys []string
+		cookie       string
+		expectBody   string
+	}{
+		{
+			name:       "no cookie",
+			expectBody: "absent",
+		},
+		{
+			name:       "encrypted with app key",
+			cookie:     "session=" + encryptWithKey(testAppKey, "session", "goravel"),
+			expectBody: "goravel",
+		},
+		{
+			name:       "encrypted with a previous key",
+			config:     EncryptCookiesConfig{PreviousKeys: []string{testPreviousKey}},
+			cookie:     "session=" + encryptWithKey(testPreviousKey, "session", "goravel"),
+			expectBody: "goravel",
+		},
+		{
+			name:         "previous keys of the config",
+			previousKeys: []string{testPreviousKey},
+			cookie:       "session=" + encryptWithKey(testPreviousKey, "session", "goravel"),
+			expectBody:   "goravel",
+		},
+		{
+			name:       "unknown key",
+			cookie:     "session=" + encryptWithKey(testPreviousKey, "session", "goravel"),
+			expectBody: "absent",
+		},
+		{
+			name:       "tampered",
+			cookie:     "session=" + encryptWithKey(testAppKey, "session", "goravel")[2:],
+			expectBody: "absent",
+		},
+		{
+			name:       "invalid iv",
+			cookie:     "session=" + base64.StdEncoding.EncodeToString([]byte(`{"iv":"AQ==","value":"AQ=="}`)),
+			expectBody: "absent",
+		},
+		{
+			name:       "moved from another cookie",
+			cookie:     "session=" + encryptWithKey(testAppKey, "remember", "goravel"),
+			expectBody: "absent",
+		},
+		{
+			name:       "plain text",
+			cookie:     "session=goravel",
+			expectBody: "absent",
+		},
+		{
+			name:       "excluded",
+			config:     EncryptCookiesConfig{Except: []string{"session"}},
+			cookie:     "session=goravel",
+			expectBody: "goravel",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newEncryptCookiesTestApp(t, test.previousKeys)
+
+			app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().String(http.StatusOK, ctx.Request().Cookie("session", "absent"))
+			}, EncryptCookies(test.config))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if test.cookie != "" {
+				req.Header.Set("Cookie", test.cookie)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectBody, string(body))
+		})
+	}
+
+	assert.Panics(t, func() {
+		EncryptCookies(EncryptCookiesConfig{PreviousKeys: []string{"short"}})
+	})
+}
+
+func TestEncryptCookiesResponse(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	newEncryptCookiesTestApp(t, nil)
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().
+			Cookie(contractshttp.Cookie{Name: "session", Value: "goravel", Path: "/", HttpOnly: true}).
+			Cookie(contractshttp.Cookie{Name: "theme", Value: "dark"}).
+			Cookie(contractshttp.Cookie{Name: "XSRF-TOKEN", Value: "token"}).
+			WithoutCookie("remember").
+			String(http.StatusOK, "ok")
+	}, EncryptCookies(EncryptCookiesConfig{Except: []string{"theme"}}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range resp.Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	require.Len(t, cookies, 4)
+
+	session := cookies["session"]
+	assert.NotEqual(t, "goravel", session.Value)
+	assert.True(t, session.HttpOnly)
+	assert.Equal(t, "/", session.Path)
+	value, ok := decryptCookie(newTestAES(t, testAppKey), testAppKey, nil, "session", session.Value)
+	assert.True(t, ok)
+	assert.Equal(t, "goravel", value)
+
+	assert.Equal(t, "dark", cookies["theme"].Value)
+	assert.Equal(t, "token", cookies["XSRF-TOKEN"].Value)
+	assert.Empty(t, cookies["remember"].Value)
+	assert.Equal(t, "goravel:encrypt_cookies", EncryptCookies().Signature())
+}
+
+func TestEncryptCookiesPreviousKeysCreatedOnce(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	mockApp := mocksfoundation.NewApplication(t)
+	mockConfig := mocksconfig.NewConfig(t)
+	mockApp.EXPECT().MakeConfig().Return(mockConfig).Twice()
+	mockApp.EXPECT().MakeCrypt().Return(newTestAES(t, testAppKey)).Twice()
+	mockApp.EXPECT().Json().Return(json.New()).Once()
+	mockConfig.EXPECT().GetString("app.key").Return(testAppKey).Twice()
+	mockConfig.EXPECT().Get("app.previous_keys").Return([]string{testPreviousKey}).Once()
+	App = mockApp
+
+	aes := newTestAES(t, testPreviousKey)
+	encrypted, err := aes.EncryptString(signCookieValue(testPreviousKey, "session", "goravel"))
+	require.NoError(t, err)
+
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, ctx.Request().Cookie("session", "absent"))
+	}, EncryptCookies())
+
+	for range 2 {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Cookie", "session="+encrypted)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "goravel", string(body))
+	}
+}
+
+func TestEncryptCookiesWithoutCrypt(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, ctx.Request().Cookie("session", "absent"))
+	}
+
+	tests := []struct {
+		name  string
+		setup func()
+	}{
+		{
+			name: "no app",
+			setup: func() {
+				App = nil
+			},
+		},
+		{
+			name: "no crypt service",
+			setup: func() {
+				mockApp := mocksfoundation.NewApplication(t)
+				mockApp.EXPECT().MakeConfig().Return(mocksconfig.NewConfig(t)).Once()
+				mockApp.EXPECT().MakeCrypt().Return(nil).Once()
+				App = mockApp
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.setup()
+			app := newMiddlewareTestApp(handler, EncryptCookies())
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Cookie", "session=goravel")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "goravel", string(body))
+		})
+	}
+}
+
+// newEncryptCookiesTestApp sets App with the crypt service of testAppKey and the previous keys of the config.
+func newEncryptCookiesTestApp(t *testing.T, previousKeys []string) {
+	mockApp := mocksfoundation.NewApplication(t)
+	mockConfig := mocksconfig.NewConfig(t)
+	mockApp.EXPECT().MakeConfig().Return(mockConfig).Once()
+	mockApp.EXPECT().MakeCrypt().Return(newTestAES(t, testAppKey)).Once()
+	mockApp.EXPECT().Json().Return(json.New()).Once()
+	mockConfig.EXPECT().GetString("app.key").Return(testAppKey).Once()
+	mockConfig.EXPECT().Get("app.previous_keys").Return(previousKeys).Maybe()
+	App = mockApp
+}
+
+func newTestAES(t *testing.T, key string) *crypt.AES {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().GetString("app.key").Return(key).Once()
+	aes, err := crypt.NewAES(mockConfig, json.New())
+	require.NoError(t, err)
+
+	return aes
+}
@@ -0,0 +1,147 @@
+package fiber
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/etag"
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+// ETagConfig configures the ETag middleware, the zero value uses the defaults.
+type ETagConfig struct {
+	// Weak generates weak ETags, they survive the transformations which don't change the meaning of the body,
+	// e.g. compression. Default: false
+	Weak bool
+	// Current returns the current ETag of the resource targeted by an unsafe request, it's compared with
+	// If-Match before the handler runs. An empty string means the resource doesn't exist.
+	// If-Match is ignored if it's nil.
+	Current func(ctx contractshttp.Context) string
+}
+
+type etagMiddleware struct {
+	config ETagConfig
+}
+
+func (m *etagMiddleware) Signature() string {
+	return "goravel:etag"
+}
+
+func (m *etagMiddleware) Handle(ctx contractshttp.Context) {
+	c := ctx.(*Context).Instance()
+
+	method := c.Method()
+	if method != fiber.MethodGet && method != fiber.MethodHead {
+		if ifMatch := c.Get(fiber.HeaderIfMatch); ifMatch != "" && m.config.Current != nil &&
+			!matchIfMatch(ifMatch, m.config.Current(ctx)) {
+			ctx.Request().Abort(http.StatusPreconditionFailed)
+			return
+		}
+
+		ctx.Request().Next()
+		return
+	}
+
+	ctx.Request().Next()
+
+	if invalidFiber(c) {
+		return
+	}
+
+	response := c.Response()
+	if response.StatusCode() != http.StatusOK || response.IsBodyStream() {
+		return
+	}
+
+	current := c.GetRespHeader(fiber.HeaderETag)
+	if current == "" {
+		body := response.Body()
+		if len(body) == 0 {
+			return
+		}
+		if m.config.Weak {
+			current = string(etag.GenerateWeak(body))
+		} else {
+			current = string(etag.Generate(body))
+		}
+		c.Set(fiber.HeaderETag, current)
+	}
+
+	// If-Modified-Since is ignored when If-None-Match is present, see RFC 9110 section 13.1.3.
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		if matchIfNoneMatch(ifNoneMatch, current) {
+			notModified(c)
+		}
+		return
+	}
+
+	if ifModifiedSince := c.Get(fiber.HeaderIfModifiedSince); ifModifiedSince != "" {
+		lastModified, err := http.ParseTime(c.GetRespHeader(fiber.HeaderLastModified))
+		if err != nil {
+			return
+		}
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return
+		}
+		if !lastModified.After(since) {
+			notModified(c)
+		}
+	}
+}
+
+// ETag creates middleware to generate ETags for the Json, String, Data and Html responses and to answer the
+// conditional requests: If-None-Match and If-Modified-Since return 304 for GET and HEAD, If-Match returns 412
+// for the unsafe methods. The handlers can set the validators via ContextResponse.ETag and ContextResponse.LastModified.
+func ETag(config ...ETagConfig) contractshttp.Middleware {
+	var cfg ETagConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return &etagMiddleware{config: cfg}
+}
+
+func notModified(c fiber.Ctx) {
+	c.Status(http.StatusNotModified)
+	c.Response().ResetBody()
+	c.Response().Header.Del(fiber.HeaderContentLength)
+}
+
+// matchIfNoneMatch uses the weak comparison, see RFC 9110 section 13.1.2.
+func matchIfNoneMatch(header, current string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	current = strings.TrimPrefix(current, "W/")
+	for part := range strings.SplitSeq(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(part), "W/") == current {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchIfMatch uses the strong comparison, so weak ETags never match, see RFC 9110 section 13.1.1.
+func matchIfMatch(header, current string) bool {
+	if current == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	if strings.HasPrefix(current, "W/") {
+		return false
+	}
+
+	for part := range strings.SplitSeq(header, ",") {
+		if strings.TrimSpace(part) == current {
+			return true
+		}
+	}
+
+	return false
+}
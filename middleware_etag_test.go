@@ -0,0 +1,176 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	lastModified := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	explicitHandler := func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.Response().(*ContextResponse).ETag("v1").LastModified(lastModified)
+		return ctx.Response().Json(http.StatusOK, map[string]string{"name": "goravel"})
+	}
+
+	tests := []struct {
+		name         string
+		config       ETagConfig
+		handler      contractshttp.HandlerFunc
+		headers      map[string]string
+		expectStatus int
+		expectETag   string
+		expectBody   string
+	}{
+		{
+			name:         "generate a strong etag",
+			expectStatus: http.StatusOK,
+			expectETag:   `"2-4009655773"`,
+			expectBody:   "ok",
+		},
+		{
+			name:         "generate a weak etag",
+			config:       ETagConfig{Weak: true},
+			expectStatus: http.StatusOK,
+			expectETag:   `W/"2-4009655773"`,
+			expectBody:   "ok",
+		},
+		{
+			name:         "if-none-match matches the generated etag",
+			headers:      map[string]string{"If-None-Match": `"x", W/"2-4009655773"`},
+			expectStatus: http.StatusNotModified,
+			expectETag:   `"2-4009655773"`,
+		},
+		{
+			name:         "if-none-match doesn't match",
+			headers:      map[string]string{"If-None-Match": `"x"`},
+			expectStatus: http.StatusOK,
+			expectETag:   `"2-4009655773"`,
+			expectBody:   "ok",
+		},
+		{
+			name:         "if-none-match matches the explicit etag",
+			handler:      explicitHandler,
+			headers:      map[string]string{"If-None-Match": `"v1"`},
+			expectStatus: http.StatusNotModified,
+			expectETag:   `"v1"`,
+		},
+		{
+			name:    "if-none-match takes precedence over if-modified-since",
+			handler: explicitHandler,
+			headers: map[string]string{
+				"If-None-Match":     `"v0"`,
+				"If-Modified-Since": lastModified.Format(http.TimeFormat),
+			},
+			expectStatus: http.StatusOK,
+			expectETag:   `"v1"`,
+			expectBody:   `{"name":"goravel"}`,
+		},
+		{
+			name:         "not modified since",
+			handler:      explicitHandler,
+			headers:      map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			expectStatus: http.StatusNotModified,
+			expectETag:   `"v1"`,
+		},
+		{
+			name:         "modified since",
+			handler:      explicitHandler,
+			headers:      map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)},
+			expectStatus: http.StatusOK,
+			expectETag:   `"v1"`,
+			expectBody:   `{"name":"goravel"}`,
+		},
+		{
+			name: "skip the error response",
+			handler: func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().String(http.StatusNotFound, "not found")
+			},
+			headers:      map[string]string{"If-None-Match": "*"},
+			expectStatus: http.StatusNotFound,
+			expectBody:   "not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newMiddlewareTestApp(test.handler, ETag(test.config))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			body := make([]byte, 64)
+			n, _ := resp.Body.Read(body)
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+			assert.Equal(t, test.expectETag, resp.Header.Get("ETag"))
+			assert.Equal(t, test.expectBody, string(body[:n]))
+		})
+	}
+}
+
+func TestETagIfMatch(t *testing.T) {
+	current := `"v1"`
+	app := fiber.New()
+	handlers := middlewaresToFiberHandlers([]contractshttp.Middleware{ETag(ETagConfig{
+		Current: func(ctx contractshttp.Context) string {
+			return current
+		},
+	})})
+	handlers = append(handlers, handlerToFiberHandler(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().NoContent()
+	}))
+	first, rest := fiberHandlerArgs(handlers)
+	app.Put("/", first, rest...)
+
+	tests := []struct {
+		name         string
+		current      string
+		ifMatch      string
+		expectStatus int
+	}{
+		{name: "no if-match", current: `"v1"`, expectStatus: http.StatusNoContent},
+		{name: "match", current: `"v1"`, ifMatch: `"v0", "v1"`, expectStatus: http.StatusNoContent},
+		{name: "mismatch", current: `"v2"`, ifMatch: `"v1"`, expectStatus: http.StatusPreconditionFailed},
+		{name: "weak etags never match", current: `W/"v1"`, ifMatch: `W/"v1"`, expectStatus: http.StatusPreconditionFailed},
+		{name: "wildcard matches an existing resource", current: `"v1"`, ifMatch: "*", expectStatus: http.StatusNoContent},
+		{name: "wildcard doesn't match a missing resource", current: "", ifMatch: "*", expectStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			current = test.current
+
+			req := httptest.NewRequest("PUT", "/", nil)
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestContextResponseValidators(t *testing.T) {
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		response := ctx.Response().(*ContextResponse)
+		response.ETag(`"quoted"`, true).LastModified(time.Date(2025, 1, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600)))
+		return response.String(http.StatusOK, "ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, `W/"quoted"`, resp.Header.Get("ETag"))
+	assert.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", resp.Header.Get("Last-Modified"))
+}
@@ -0,0 +1,235 @@
+package fiber
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/json"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLength bounds the key sent by the client.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyConfig configures the Idempotency middleware, the zero value uses the defaults.
+type IdempotencyConfig struct {
+	// Methods are the methods which read Idempotency-Key. Default: POST, PATCH
+	Methods []string
+	// Required rejects the requests without Idempotency-Key with 400.
+	Required bool
+	// TTL is how long the first response is replayed. Default: 24 hours
+	TTL time.Duration
+	// LockTimeout releases the lock of a request which never finishes, e.g. the process crashes. Default: 1 minute
+	LockTimeout time.Duration
+	// Scope separates the keys of different clients, so a client can't replay the response of another one.
+	// Default: the ID of the authenticated user, the client IP for the guests.
+	Scope func(ctx contractshttp.Context) string
+	// Store Default: a memory store shared by all Idempotency middleware.
+	Store Store
+}
+
+var defaultIdempotencyStore = NewMemoryStore()
+
+type idempotentResponse struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Headers     [][2]string `json:"headers"`
+	Body        []byte      `json:"body"`
+}
+
+type idempotencyMiddleware struct {
+	config IdempotencyConfig
+}
+
+func (m *idempotencyMiddleware) Signature() string {
+	return "goravel:idempotency"
+}
+
+func (m *idempotencyMiddleware) Handle(ctx contractshttp.Context) {
+	request := ctx.Request()
+	if !slices.Contains(m.config.Methods, request.Method()) {
+		request.Next()
+		return
+	}
+
+	key := request.Header(HeaderIdempotencyKey)
+	if key == "" {
+		if m.config.Required {
+			request.Abort(http.StatusBadRequest)
+			return
+		}
+		request.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		request.Abort(http.StatusBadRequest)
+		return
+	}
+
+	storeKey := m.storeKey(ctx, key)
+	lockKey := storeKey + ":lock"
+	bodyHash := sha256.Sum256(ctx.(*Context).Instance().Body())
+	fingerprint := hex.EncodeToString(bodyHash[:])
+
+	// The store errors fail closed, running a payment twice is worse than rejecting a retry.
+	replayed, err := m.replay(ctx, storeKey, fingerprint)
+	if err != nil {
+		m.fail(ctx, fmt.Errorf("idempotency failed to get %s: %w", storeKey, err))
+		return
+	}
+	if replayed {
+		return
+	}
+
+	locked, err := m.config.Store.Add(ctx, lockKey, []byte("1"), m.config.LockTimeout)
+	if err != nil {
+		m.fail(ctx, fmt.Errorf("idempotency failed to lock %s: %w", storeKey, err))
+		return
+	}
+	if !locked {
+		// The first request with the key is still in progress.
+		request.Abort(http.StatusConflict)
+		return
+	}
+	defer func() {
+		if err := m.config.Store.Forget(ctx, lockKey); err != nil {
+			LogFacade.Error(fmt.Errorf("idempotency failed to unlock %s: %w", storeKey, err))
+		}
+	}()
+
+	// The first request may have finished between the lookup and the lock.
+	replayed, err = m.replay(ctx, storeKey, fingerprint)
+	if err != nil {
+		m.fail(ctx, fmt.Errorf("idempotency failed to get %s: %w", storeKey, err))
+		return
+	}
+	if replayed {
+		return
+	}
+
+	request.Next()
+
+	if invalidFiber(ctx.(*Context).Instance()) {
+		return
+	}
+
+	origin := ctx.Response().Origin()
+	// A server error isn't stored, so the retry can succeed.
+	if origin.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	response := idempotentResponse{
+		Fingerprint: fingerprint,
+		Status:      origin.Status(),
+		Body:        origin.Body().Bytes(),
+	}
+	for name, values := range origin.Header() {
+		// The cookies, e.g. a new session, belong to the first request only.
+		if isPerResponseHeader(name) || strings.EqualFold(name, fiber.HeaderSetCookie) {
+			continue
+		}
+		for _, value := range values {
+			response.Headers = append(response.Headers, [2]string{name, value})
+		}
+	}
+
+	value, err := json.Marshal(response)
+	if err == nil {
+		err = m.config.Store.Put(ctx, storeKey, value, m.config.TTL)
+	}
+	if err != nil {
+		LogFacade.Error(fmt.Errorf("idempotency failed to put %s: %w", storeKey, err))
+	}
+}
+
+// replay writes the stored response of the key, a request with a different body is rejected with 422.
+func (m *idempotencyMiddleware) replay(ctx contractshttp.Context, storeKey, fingerprint string) (bool, error) {
+	value, err := m.config.Store.Get(ctx, storeKey)
+	if err != nil || value == nil {
+		return false, err
+	}
+
+	var response idempotentResponse
+	if err := json.Unmarshal(value, &response); err != nil {
+		return false, err
+	}
+
+	if response.Fingerprint != fingerprint {
+		ctx.Request().Abort(http.StatusUnprocessableEntity)
+		return true, nil
+	}
+
+	c := ctx.(*Context).Instance()
+	headers := slices.DeleteFunc(response.Headers, func(header [2]string) bool {
+		return strings.EqualFold(header[0], fiber.HeaderSetCookie)
+	})
+	writeStoredResponse(c, response.Status, headers, response.Body)
+	c.Set(HeaderIdempotentReplayed, "true")
+
+	return true, nil
+}
+
+// storeKey scopes the key to the client, the method and the path.
+func (m *idempotencyMiddleware) storeKey(ctx contractshttp.Context, key string) string {
+	scope := m.config.Scope(ctx)
+	hash := sha256.Sum256([]byte(scope + "\n" + ctx.Request().Method() + "\n" + ctx.Request().Path() + "\n" + key))
+
+	return "idempotency:" + hex.EncodeToString(hash[:])
+}
+
+// defaultIdempotencyScope scopes the keys to the authenticated user, or to the client IP for the guests.
+func defaultIdempotencyScope(ctx contractshttp.Context) string {
+	if App != nil {
+		if auth := App.MakeAuth(ctx); auth != nil {
+			if id, err := auth.ID(); err == nil && id != "" {
+				return "user:" + id
+			}
+		}
+	}
+
+	return "ip:" + ctx.Request().Ip()
+}
+
+func (m *idempotencyMiddleware) fail(ctx contractshttp.Context, err error) {
+	LogFacade.Error(err)
+	ctx.Request().Abort(http.StatusServiceUnavailable)
+}
+
+// Idempotency creates middleware to make the retries of the requests with Idempotency-Key safe. The first response
+// of a key is stored and replayed to the repeated requests with Idempotent-Replayed: true, the concurrent duplicates
+// are rejected with 409 and a key reused with a different body is rejected with 422.
+func Idempotency(config ...IdempotencyConfig) contractshttp.Middleware {
+	var cfg IdempotencyConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = time.Minute
+	}
+	if cfg.Scope == nil {
+		cfg.Scope = defaultIdempotencyScope
+	}
+	if cfg.Store == nil {
+		cfg.Store = defaultIdempotencyStore
+	}
+
+	return &idempotencyMiddleware{config: cfg}
+}
@@ -0,0 +1,302 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mocksauth "github.com/goravel/framework/mocks/auth"
+	mocksfoundation "github.com/goravel/framework/mocks/foundation"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		method         string
+		key            string
+		user           string
+		ip             string
+		body           string
+		expectStatus   int
+		expectBody     string
+		expectReplayed bool
+		expectCalled   int32
+	}
+
+	tests := []struct {
+		name     string
+		config   IdempotencyConfig
+		requests []request
+	}{
+		{
+			name: "replay the first response",
+			requests: []request{
+				{key: "a", body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectCalled: 1},
+				{key: "a", body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectReplayed: true, expectCalled: 1},
+				{method: "PATCH", key: "a", body: "order", expectStatus: http.StatusCreated, expectBody: "created 2", expectCalled: 2},
+				{key: "b", body: "order", expectStatus: http.StatusCreated, expectBody: "created 3", expectCalled: 3},
+			},
+		},
+		{
+			name: "reject the key reused with a different body",
+			requests: []request{
+				{key: "a", body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectCalled: 1},
+				{key: "a", body: "another order", expectStatus: http.StatusUnprocessableEntity, expectBody: "Unprocessable Entity", expectCalled: 1},
+			},
+		},
+		{
+			name: "skip the requests without the key or with other methods",
+			requests: []request{
+				{body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectCalled: 1},
+				{body: "order", expectStatus: http.StatusCreated, expectBody: "created 2", expectCalled: 2},
+				{method: "PUT", key: "a", body: "order", expectStatus: http.StatusCreated, expectBody: "created 3", expectCalled: 3},
+				{method: "PUT", key: "a", body: "order", expectStatus: http.StatusCreated, expectBody: "created 4", expectCalled: 4},
+			},
+		},
+		{
+			name:   "require the key",
+			config: IdempotencyConfig{Required: true},
+			requests: []request{
+				{body: "order", expectStatus: http.StatusBadRequest, expectBody: "Bad Request"},
+				{key: strings.Repeat("a", 256), body: "order", expectStatus: http.StatusBadRequest, expectBody: "Bad Request"},
+			},
+		},
+		{
+			name: "don't store the server errors",
+			requests: []request{
+				{key: "a", body: "fail", expectStatus: http.StatusInternalServerError, expectBody: "failed 1", expectCalled: 1},
+				{key: "a", body: "fail", expectStatus: http.StatusInternalServerError, expectBody: "failed 2", expectCalled: 2},
+			},
+		},
+		{
+			name: "scope the keys",
+			config: IdempotencyConfig{Scope: func(ctx contractshttp.Context) string {
+				return ctx.Request().Header("X-User")
+			}},
+			requests: []request{
+				{key: "a", user: "1", body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectCalled: 1},
+				{key: "a", user: "2", body: "order", expectStatus: http.StatusCreated, expectBody: "created 2", expectCalled: 2},
+				{key: "a", user: "1", body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectReplayed: true, expectCalled: 2},
+			},
+		},
+		{
+			name: "scope the keys to the client IP by default",
+			requests: []request{
+				{key: "a", ip: "1.1.1.1", body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectCalled: 1},
+				{key: "a", ip: "2.2.2.2", body: "order", expectStatus: http.StatusCreated, expectBody: "created 2", expectCalled: 2},
+				{key: "a", ip: "1.1.1.1", body: "order", expectStatus: http.StatusCreated, expectBody: "created 1", expectReplayed: true, expectCalled: 2},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.Store = NewMemoryStore()
+
+			var called atomic.Int32
+			app := newTrustedMiddlewareTestApp(true, func(ctx contractshttp.Context) contractshttp.Response {
+				n := called.Add(1)
+				if string(ctx.(*Context).Instance().Body()) == "fail" {
+					return ctx.Response().String(http.StatusInternalServerError, fmt.Sprintf("failed %d", n))
+				}
+				ctx.Response().Header("X-Order", fmt.Sprint(n))
+				ctx.Response().Header("Set-Cookie", fmt.Sprintf("session=%d", n))
+				return ctx.Response().String(http.StatusCreated, fmt.Sprintf("created %d", n))
+			}, Idempotency(test.config))
+
+			for i, r := range test.requests {
+				method := r.method
+				if method == "" {
+					method = "POST"
+				}
+				req := httptest.NewRequest(method, "/orders", strings.NewReader(r.body))
+				if r.key != "" {
+					req.Header.Set(HeaderIdempotencyKey, r.key)
+				}
+				req.Header.Set("X-User", r.user)
+				if r.ip != "" {
+					req.Header.Set("X-Forwarded-For", r.ip)
+				}
+
+				resp, err := app.Test(req)
+				require.NoError(t, err)
+
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, r.expectStatus, resp.StatusCode, "request %d", i)
+				assert.Equal(t, r.expectBody, string(body), "request %d", i)
+				assert.Equal(t, r.expectCalled, called.Load(), "request %d", i)
+				if r.expectReplayed {
+					assert.Equal(t, "true", resp.Header.Get(HeaderIdempotentReplayed), "request %d", i)
+					assert.Equal(t, strings.TrimPrefix(r.expectBody, "created "), resp.Header.Get("X-Order"), "request %d", i)
+					assert.Empty(t, resp.Header.Get("Set-Cookie"), "request %d", i)
+				} else {
+					assert.Empty(t, resp.Header.Get(HeaderIdempotentReplayed), "request %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyConcurrentDuplicate(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		close(entered)
+		<-release
+		return ctx.Response().String(http.StatusCreated, "created")
+	}, Idempotency(IdempotencyConfig{Store: NewMemoryStore()}))
+
+	send := func() *http.Response {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader("order"))
+		req.Header.Set(HeaderIdempotencyKey, "a")
+		resp, err := app.Test(req, fiber.TestConfig{Timeout: 0})
+		require.NoError(t, err)
+		return resp
+	}
+
+	first := make(chan *http.Response)
+	go func() {
+		first <- send()
+	}()
+	<-entered
+
+	assert.Equal(t, http.StatusConflict, send().StatusCode)
+
+	close(release)
+	assert.Equal(t, http.StatusCreated, (<-first).StatusCode)
+
+	resp := send()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(HeaderIdempotentReplayed))
+}
+
+func TestIdempotencyReplayHeadersOfOuterMiddleware(t *testing.T) {
+	var id atomic.Int32
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.Response().Header("X-Custom", "handler")
+		return ctx.Response().String(http.StatusCreated, "created")
+	}, RequestID(RequestIDConfig{Generator: func() string {
+		return fmt.Sprintf("request-%d", id.Add(1))
+	}}), &headerMiddleware{headers: map[string]string{"X-Custom": "outer"}}, Idempotency(IdempotencyConfig{
+		Store: NewMemoryStore(),
+	}))
+
+	for i, expectReplayed := range []string{"", "true"} {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader("order"))
+		req.Header.Set(HeaderIdempotencyKey, "a")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectReplayed, resp.Header.Get(HeaderIdempotentReplayed))
+		assert.Equal(t, []string{fmt.Sprintf("request-%d", i+1)}, resp.Header.Values(HeaderRequestID))
+		assert.Equal(t, []string{"handler"}, resp.Header.Values("X-Custom"))
+	}
+}
+
+func TestIdempotencyDefaultScope(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	tests := []struct {
+		name        string
+		setup       func(mockApp *mocksfoundation.Application, mockAuth *mocksauth.Auth)
+		expectScope string
+	}{
+		{
+			name: "the authenticated user",
+			setup: func(mockApp *mocksfoundation.Application, mockAuth *mocksauth.Auth) {
+				mockApp.EXPECT().MakeAuth(mock.Anything).Return(mockAuth).Once()
+				mockAuth.EXPECT().ID().Return("1", nil).Once()
+			},
+			expectScope: "user:1",
+		},
+		{
+			name: "the client IP of a guest",
+			setup: func(mockApp *mocksfoundation.Application, mockAuth *mocksauth.Auth) {
+				mockApp.EXPECT().MakeAuth(mock.Anything).Return(mockAuth).Once()
+				mockAuth.EXPECT().ID().Return("", errors.New("unauthorized")).Once()
+			},
+			expectScope: "ip:0.0.0.0",
+		},
+		{
+			name: "the client IP without auth",
+			setup: func(mockApp *mocksfoundation.Application, mockAuth *mocksauth.Auth) {
+				mockApp.EXPECT().MakeAuth(mock.Anything).Return(nil).Once()
+			},
+			expectScope: "ip:0.0.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockApp := mocksfoundation.NewApplication(t)
+			App = mockApp
+			test.setup(mockApp, mocksauth.NewAuth(t))
+
+			app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().String(http.StatusOK, defaultIdempotencyScope(ctx))
+			})
+
+			resp, err := app.Test(httptest.NewRequest("POST", "/orders", nil))
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectScope, string(body))
+		})
+	}
+}
+
+func TestIdempotencyStoreError(t *testing.T) {
+	mockLog := mockslog.NewLog(t)
+	mockLog.EXPECT().Error(mock.Anything).Once()
+	LogFacade = mockLog
+
+	app := newMiddlewareTestApp(nil, Idempotency(IdempotencyConfig{Store: &brokenIdempotencyStore{}}))
+
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader("order"))
+	req.Header.Set(HeaderIdempotencyKey, "a")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+type brokenIdempotencyStore struct{}
+
+func (s *brokenIdempotencyStore) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (s *brokenIdempotencyStore) Put(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (s *brokenIdempotencyStore) Add(context.Context, string, []byte, time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (s *brokenIdempotencyStore) Forget(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func (s *brokenIdempotencyStore) Increment(context.Context, string, time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (s *brokenIdempotencyStore) GetInt64(context.Context, string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
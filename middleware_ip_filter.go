@@ -0,0 +1,147 @@
+package fiber
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/spf13/cast"
+)
+
+// IPFilterConfig configures the IPFilter middleware. The entries are IPv4 or IPv6 addresses or CIDRs.
+type IPFilterConfig struct {
+	// Allow restricts the requests to the entries if it isn't empty.
+	Allow []string
+	// Deny blocks the entries, it's checked before Allow.
+	Deny []string
+	// Status is the response status of the blocked requests. Default: 403
+	Status int
+	// ConfigKey reads the allow, deny and status items under the key from the config on every request instead of
+	// the fields above, e.g. "http.ip_filter.admin", so the lists can be changed without restarting the server.
+	ConfigKey string
+}
+
+type ipFilterRules struct {
+	allow  []*net.IPNet
+	deny   []*net.IPNet
+	status int
+}
+
+type ipFilterMiddleware struct {
+	configKey string
+	rules     *ipFilterRules
+
+	// The rules of ConfigKey are parsed again only when the config changes.
+	mu          sync.Mutex
+	loadedFrom  []string
+	invalidFrom []string
+}
+
+func (m *ipFilterMiddleware) Signature() string {
+	if m.configKey != "" {
+		return "goravel:ip_filter:" + m.configKey
+	}
+
+	return "goravel:ip_filter"
+}
+
+func (m *ipFilterMiddleware) Handle(ctx contractshttp.Context) {
+	rules, err := m.currentRules()
+	if err != nil {
+		LogFacade.Error(err)
+	}
+	if rules == nil {
+		// The filter fails closed, an invalid allow list must not open the route to everyone.
+		ctx.Request().Abort(http.StatusForbidden)
+		return
+	}
+
+	if !rules.allowed(net.ParseIP(ctx.Request().Ip())) {
+		ctx.Request().Abort(rules.status)
+		return
+	}
+
+	ctx.Request().Next()
+}
+
+func (m *ipFilterMiddleware) currentRules() (*ipFilterRules, error) {
+	if m.configKey == "" {
+		return m.rules, nil
+	}
+
+	allow := cast.ToStringSlice(ConfigFacade.Get(m.configKey + ".allow"))
+	deny := cast.ToStringSlice(ConfigFacade.Get(m.configKey + ".deny"))
+	status := ConfigFacade.GetInt(m.configKey+".status", http.StatusForbidden)
+	loadedFrom := slices.Concat(allow, []string{"|"}, deny, []string{"|", cast.ToString(status)})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rules != nil && slices.Equal(m.loadedFrom, loadedFrom) {
+		return m.rules, nil
+	}
+	// The invalid config is reported once, the last valid rules are kept until it's fixed.
+	if m.invalidFrom != nil && slices.Equal(m.invalidFrom, loadedFrom) {
+		return m.rules, nil
+	}
+
+	rules, err := newIPFilterRules(allow, deny, status)
+	if err != nil {
+		m.invalidFrom = loadedFrom
+		return m.rules, fmt.Errorf("invalid ip filter %s: %w", m.configKey, err)
+	}
+	m.rules, m.loadedFrom, m.invalidFrom = rules, loadedFrom, nil
+
+	return rules, nil
+}
+
+func (r *ipFilterRules) allowed(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if containsIP(r.deny, ip) {
+		return false
+	}
+
+	return len(r.allow) == 0 || containsIP(r.allow, ip)
+}
+
+// IPFilter creates middleware to restrict the requests by the client IP, which is resolved with the trusted_proxies
+// and proxy_header of the driver config. It panics if an entry is invalid.
+func IPFilter(config IPFilterConfig) contractshttp.Middleware {
+	if config.ConfigKey != "" {
+		return &ipFilterMiddleware{configKey: config.ConfigKey}
+	}
+
+	rules, err := newIPFilterRules(config.Allow, config.Deny, config.Status)
+	if err != nil {
+		panic(err)
+	}
+
+	return &ipFilterMiddleware{rules: rules}
+}
+
+func newIPFilterRules(allow, deny []string, status int) (*ipFilterRules, error) {
+	allowNetworks, err := parseTrustedNetworks(allow)
+	if err != nil {
+		return nil, err
+	}
+	denyNetworks, err := parseTrustedNetworks(deny)
+	if err != nil {
+		return nil, err
+	}
+	if status == 0 {
+		status = http.StatusForbidden
+	}
+
+	return &ipFilterRules{allow: allowNetworks, deny: denyNetworks, status: status}, nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	return slices.ContainsFunc(networks, func(network *net.IPNet) bool {
+		return network.Contains(ip)
+	})
+}
@@ -0,0 +1,126 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocksconfig "github.com/goravel/framework/mocks/config"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPFilter(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       IPFilterConfig
+		trustProxy   bool
+		ip           string
+		expectStatus int
+	}{
+		{
+			name:         "allow a single ip",
+			config:       IPFilterConfig{Allow: []string{"10.0.0.1"}},
+			trustProxy:   true,
+			ip:           "10.0.0.1",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "allow a cidr",
+			config:       IPFilterConfig{Allow: []string{"192.168.0.0/16"}},
+			trustProxy:   true,
+			ip:           "192.168.10.20",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "block the ip outside the allow list",
+			config:       IPFilterConfig{Allow: []string{"192.168.0.0/16"}},
+			trustProxy:   true,
+			ip:           "10.0.0.1",
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "allow an ipv6 cidr",
+			config:       IPFilterConfig{Allow: []string{"2001:db8::/32"}},
+			trustProxy:   true,
+			ip:           "2001:db8::1",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "deny takes precedence",
+			config:       IPFilterConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.1"}, Status: http.StatusNotFound},
+			trustProxy:   true,
+			ip:           "10.0.0.1",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "only deny",
+			config:       IPFilterConfig{Deny: []string{"10.0.0.0/8"}},
+			trustProxy:   true,
+			ip:           "172.16.0.1",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ignore the forwarded ip of an untrusted client",
+			config:       IPFilterConfig{Allow: []string{"10.0.0.1"}},
+			ip:           "10.0.0.1",
+			expectStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newTrustedMiddlewareTestApp(test.trustProxy, nil, IPFilter(test.config))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-Forwarded-For", test.ip)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+		})
+	}
+
+	assert.PanicsWithError(t, "invalid IP address: localhost", func() {
+		IPFilter(IPFilterConfig{Allow: []string{"localhost"}})
+	})
+	assert.Equal(t, "goravel:ip_filter", IPFilter(IPFilterConfig{}).Signature())
+	assert.Equal(t, "goravel:ip_filter:http.ip_filter.admin", IPFilter(IPFilterConfig{ConfigKey: "http.ip_filter.admin"}).Signature())
+}
+
+func TestIPFilterConfigKey(t *testing.T) {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockLog := mockslog.NewLog(t)
+	ConfigFacade = mockConfig
+	LogFacade = mockLog
+
+	app := newTrustedMiddlewareTestApp(true, nil, IPFilter(IPFilterConfig{ConfigKey: "http.ip_filter.admin"}))
+	request := func(allow []string, ip string) int {
+		mockConfig.EXPECT().Get("http.ip_filter.admin.allow").Return(allow).Once()
+		mockConfig.EXPECT().Get("http.ip_filter.admin.deny").Return(nil).Once()
+		mockConfig.EXPECT().GetInt("http.ip_filter.admin.status", http.StatusForbidden).Return(http.StatusForbidden).Once()
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Forwarded-For", ip)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+
+	// An invalid config fails closed.
+	mockLog.EXPECT().Error(mock.Anything).Once()
+	assert.Equal(t, http.StatusForbidden, request([]string{"invalid"}, "10.0.0.1"))
+
+	assert.Equal(t, http.StatusOK, request([]string{"10.0.0.1"}, "10.0.0.1"))
+	assert.Equal(t, http.StatusForbidden, request([]string{"10.0.0.1"}, "10.0.0.2"))
+
+	// The config is reloaded without restarting.
+	assert.Equal(t, http.StatusOK, request([]string{"10.0.0.0/24"}, "10.0.0.2"))
+
+	// The last valid rules are kept, the invalid config is reported once.
+	mockLog.EXPECT().Error(mock.Anything).Once()
+	assert.Equal(t, http.StatusOK, request([]string{"10.0.0.0/33"}, "10.0.0.2"))
+	assert.Equal(t, http.StatusOK, request([]string{"10.0.0.0/33"}, "10.0.0.2"))
+}
@@ -0,0 +1,278 @@
+package fiber
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/goravel/framework/contracts/config"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/foundation/console"
+	"github.com/spf13/cast"
+)
+
+// defaultMaintenanceExcept are the health endpoints, so the load balancers keep the instances during maintenance.
+var defaultMaintenanceExcept = []string{"health", "healthz", "livez", "readyz", "up"}
+
+// MaintenanceConfig configures the CheckForMaintenanceMode middleware, the zero value uses the defaults.
+// The maintenance mode itself is turned on and off by the artisan down and up commands.
+type MaintenanceConfig struct {
+	// AllowedIPs are the IPs or CIDRs served during maintenance, e.g. the office network.
+	AllowedIPs []string
+	// Except are the paths served during maintenance besides the health endpoints, matched via path.Match
+	// without the leading and trailing slashes, e.g. "webhooks/*".
+	Except []string
+	// BypassPath is the path prefix of the secret bypass URL, visiting <BypassPath>/<secret> sets the bypass
+	// cookie and redirects to the home page. The secret is the one passed to artisan down.
+	// Default: /maintenance
+	BypassPath string
+	// CookieName is the name of the signed bypass cookie.
+	// Default: goravel_maintenance
+	CookieName string
+	// CookieLifetime Default: 12 hours
+	CookieLifetime time.Duration
+	// RetryAfter is sent in the Retry-After header of the maintenance response.
+	// Default: 60 seconds
+	RetryAfter time.Duration
+	// ConfigKey reads allowed_ips, except and retry_after (in seconds) from the config under the key on every
+	// request during maintenance, they are added to the fields above, e.g. "http.drivers.fiber.maintenance".
+	// allowed_ips is parsed again only when it changes.
+	ConfigKey string
+}
+
+type maintenanceMiddleware struct {
+	config     MaintenanceConfig
+	allowedIPs []*net.IPNet
+
+	// The allowed IPs of ConfigKey are parsed again only when the config changes.
+	mu               sync.Mutex
+	loadedFrom       []string
+	loadedAllowedIPs []*net.IPNet
+}
+
+func (m *maintenanceMiddleware) Signature() string {
+	// It replaces the framework middleware, so excluding either of them from a route excludes this one.
+	return "goravel:check_for_maintenance_mode"
+}
+
+func (m *maintenanceMiddleware) Handle(ctx contractshttp.Context) {
+	request := ctx.Request()
+	if matchPathPatterns(m.config.Except, request.Path()) || App == nil {
+		request.Next()
+		return
+	}
+
+	configFacade, cacheFacade, storage, hash := App.MakeConfig(), App.MakeCache(), App.MakeStorage(), App.MakeHash()
+	if configFacade == nil || cacheFacade == nil || storage == nil || hash == nil {
+		request.Next()
+		return
+	}
+
+	content, exists, err := console.NewMaintenanceMode(configFacade, cacheFacade, storage).Get()
+	if err != nil {
+		abortMaintenanceMode(ctx, err)
+		return
+	}
+	if !exists {
+		request.Next()
+		return
+	}
+
+	var options console.MaintenanceOptions
+	if err := json.Unmarshal(content, &options); err != nil {
+		abortMaintenanceMode(ctx, err)
+		return
+	}
+
+	allowedIPs, except, retryAfter := m.settings(configFacade)
+	if matchPathPatterns(except, request.Path()) || containsIP(allowedIPs, net.ParseIP(request.Ip())) {
+		request.Next()
+		return
+	}
+
+	if options.Secret != "" {
+		appKey := configFacade.GetString("app.key")
+		if m.validCookie(ctx, appKey, options.Secret) {
+			request.Next()
+			return
+		}
+
+		// The secret of the bypass path is preferred, the secret query is kept for the framework middleware.
+		secret, viaPath := strings.CutPrefix(request.Path(), m.config.BypassPath+"/")
+		if !viaPath {
+			secret = request.Query("secret")
+		}
+		if secret != "" && !strings.Contains(secret, "/") && hash.Check(secret, options.Secret) {
+			m.setCookie(ctx, appKey, options.Secret)
+			if !viaPath {
+				request.Next()
+				return
+			}
+			if err := ctx.Response().Redirect(contractshttp.StatusTemporaryRedirect, "/").Abort(); err != nil {
+				panic(err)
+			}
+			return
+		}
+	}
+
+	if options.Redirect != "" {
+		if request.Path() == options.Redirect {
+			request.Next()
+			return
+		}
+		if err := ctx.Response().Redirect(contractshttp.StatusTemporaryRedirect, options.Redirect).Abort(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	status := options.Status
+	if status == 0 {
+		status = contractshttp.StatusServiceUnavailable
+	}
+
+	c := ctx.(*Context).Instance()
+	ctx.Response().Header(HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())))
+	switch {
+	case options.Render != "":
+		c.Status(status)
+		if err := ctx.Response().View().Make(options.Render).Render(); err != nil {
+			panic(err)
+		}
+	case c.Accepts(fiber.MIMETextHTML, fiber.MIMEApplicationJSON) == fiber.MIMEApplicationJSON:
+		if err := ctx.Response().Json(status, map[string]string{"message": options.Reason}).Abort(); err != nil {
+			panic(err)
+		}
+	default:
+		if err := ctx.Response().String(status, options.Reason).Abort(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// settings merges the settings of the config key into the ones of the middleware, the invalid IPs are skipped.
+func (m *maintenanceMiddleware) settings(configFacade config.Config) ([]*net.IPNet, []string, time.Duration) {
+	if m.config.ConfigKey == "" {
+		return m.allowedIPs, nil, m.config.RetryAfter
+	}
+
+	allowedIPs := m.loadAllowedIPs(cast.ToStringSlice(configFacade.Get(m.config.ConfigKey + ".allowed_ips")))
+	retryAfter := m.config.RetryAfter
+	if seconds := configFacade.GetInt(m.config.ConfigKey+".retry_after", 0); seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return allowedIPs, trimPathPatterns(cast.ToStringSlice(configFacade.Get(m.config.ConfigKey + ".except"))), retryAfter
+}
+
+// loadAllowedIPs merges the allowed IPs of the config key into the ones of the middleware, the invalid IPs are
+// reported once and skipped.
+func (m *maintenanceMiddleware) loadAllowedIPs(items []string) []*net.IPNet {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.loadedFrom != nil && slices.Equal(m.loadedFrom, items) {
+		return m.loadedAllowedIPs
+	}
+
+	// The allowed IPs of the middleware are clipped, so appending never writes to the shared array.
+	allowedIPs := slices.Clip(m.allowedIPs)
+	for _, item := range items {
+		networks, err := parseTrustedNetworks([]string{item})
+		if err != nil {
+			if LogFacade != nil {
+				LogFacade.Error(fmt.Errorf("%s.allowed_ips: %w", m.config.ConfigKey, err))
+			}
+			continue
+		}
+		allowedIPs = append(allowedIPs, networks...)
+	}
+	// loadedFrom isn't nil once loaded, so an empty list is cached as well.
+	m.loadedFrom, m.loadedAllowedIPs = append([]string{}, items...), allowedIPs
+
+	return allowedIPs
+}
+
+func (m *maintenanceMiddleware) validCookie(ctx contractshttp.Context, appKey, secretHash string) bool {
+	expires, signature, found := strings.Cut(ctx.Request().Cookie(m.config.CookieName), ".")
+	if !found {
+		return false
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(signMaintenanceCookie(appKey, secretHash, expires)))
+}
+
+func (m *maintenanceMiddleware) setCookie(ctx contractshttp.Context, appKey, secretHash string) {
+	expires := strconv.FormatInt(time.Now().Add(m.config.CookieLifetime).Unix(), 10)
+	ctx.Response().Cookie(contractshttp.Cookie{
+		Name:     m.config.CookieName,
+		Value:    expires + "." + signMaintenanceCookie(appKey, secretHash, expires),
+		MaxAge:   int(m.config.CookieLifetime.Seconds()),
+		Path:     "/",
+		Secure:   ctx.(*Context).Instance().Scheme() == "https",
+		HttpOnly: true,
+		SameSite: "Lax",
+	})
+}
+
+// CheckForMaintenanceMode creates middleware to answer the requests while the application is down for maintenance.
+// Besides the framework middleware, it serves the allowed IPs and the health endpoints, remembers the secret
+// bypass in a signed cookie, answers JSON to the clients preferring it and sends Retry-After.
+// It panics if an allowed IP is invalid.
+func CheckForMaintenanceMode(config ...MaintenanceConfig) contractshttp.Middleware {
+	var cfg MaintenanceConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	allowedIPs, err := parseTrustedNetworks(cfg.AllowedIPs)
+	if err != nil {
+		panic(err)
+	}
+
+	cfg.Except = trimPathPatterns(slices.Concat(cfg.Except, defaultMaintenanceExcept))
+	if cfg.BypassPath == "" {
+		cfg.BypassPath = "/maintenance"
+	}
+	cfg.BypassPath = "/" + strings.Trim(cfg.BypassPath, "/")
+	if cfg.CookieName == "" {
+		cfg.CookieName = "goravel_maintenance"
+	}
+	if cfg.CookieLifetime <= 0 {
+		cfg.CookieLifetime = 12 * time.Hour
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = time.Minute
+	}
+
+	return &maintenanceMiddleware{config: cfg, allowedIPs: allowedIPs}
+}
+
+// signMaintenanceCookie signs the expiry with the secret hash of artisan down, so the cookies stop working
+// when the application is taken down again with another secret.
+func signMaintenanceCookie(appKey, secretHash, expires string) string {
+	mac := hmac.New(sha256.New, []byte(appKey))
+	mac.Write([]byte(expires + "|" + secretHash))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func abortMaintenanceMode(ctx contractshttp.Context, err error) {
+	if err := ctx.Response().String(contractshttp.StatusServiceUnavailable, err.Error()).Abort(); err != nil {
+		panic(err)
+	}
+}
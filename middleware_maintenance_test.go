@@ -0,0 +1,254 @@
+package fiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockscache "github.com/goravel/framework/mocks/cache"
+	mocksconfig "github.com/goravel/framework/mocks/config"
+	mocksfilesystem "github.com/goravel/framework/mocks/filesystem"
+	mocksfoundation "github.com/goravel/framework/mocks/foundation"
+	mockshash "github.com/goravel/framework/mocks/hash"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckForMaintenanceMode(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	const configKey = "http.drivers.fiber.maintenance"
+
+	tests := []struct {
+		name string
+		// maintenance is the content of artisan down, empty means the application is up.
+		maintenance string
+		settings    map[string]any
+		path        string
+		headers     map[string]string
+		setup       func(mockHash *mockshash.Hash, mockLog *mockslog.Log)
+		expectCode  int
+		expectBody  string
+		expectRetry string
+		assert      func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:       "up",
+			path:       "/",
+			expectCode: http.StatusOK,
+			expectBody: "ok",
+		},
+		{
+			name:        "down",
+			maintenance: `{"reason":"upgrading","status":503}`,
+			path:        "/users",
+			expectCode:  http.StatusServiceUnavailable,
+			expectBody:  "upgrading",
+			expectRetry: "60",
+		},
+		{
+			name:        "json",
+			maintenance: `{"reason":"upgrading","status":503}`,
+			settings:    map[string]any{"retry_after": 120},
+			path:        "/users",
+			headers:     map[string]string{"Accept": "application/json"},
+			expectCode:  http.StatusServiceUnavailable,
+			expectBody:  `{"message":"upgrading"}`,
+			expectRetry: "120",
+		},
+		{
+			name:        "health endpoints are served",
+			maintenance: `{"reason":"upgrading","status":503}`,
+			path:        "/healthz",
+			expectCode:  http.StatusOK,
+			expectBody:  "ok",
+		},
+		{
+			name:        "configured paths are served",
+			maintenance: `{"reason":"upgrading","status":503}`,
+			settings:    map[string]any{"except": []string{"webhooks/*"}},
+			path:        "/webhooks/stripe",
+			expectCode:  http.StatusOK,
+			expectBody:  "ok",
+		},
+		{
+			name:        "allowed IPs are served",
+			maintenance: `{"reason":"upgrading","status":503}`,
+			settings:    map[string]any{"allowed_ips": []string{"invalid", "0.0.0.0/8"}},
+			path:        "/users",
+			setup: func(mockHash *mockshash.Hash, mockLog *mockslog.Log) {
+				mockLog.EXPECT().Error(mock.Anything).Once()
+			},
+			expectCode: http.StatusOK,
+			expectBody: "ok",
+		},
+		{
+			name:        "bypass path sets the cookie",
+			maintenance: `{"reason":"upgrading","status":503,"secret":"hashed"}`,
+			path:        "/maintenance/secret",
+			setup: func(mockHash *mockshash.Hash, mockLog *mockslog.Log) {
+				mockHash.EXPECT().Check("secret", "hashed").Return(true).Once()
+			},
+			expectCode: http.StatusTemporaryRedirect,
+			assert: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, "/", resp.Header.Get("Location"))
+				require.Len(t, resp.Cookies(), 1)
+				assert.Equal(t, "goravel_maintenance", resp.Cookies()[0].Name)
+				assert.True(t, resp.Cookies()[0].HttpOnly)
+			},
+		},
+		{
+			name:        "wrong secret",
+			maintenance: `{"reason":"upgrading","status":503,"secret":"hashed"}`,
+			path:        "/maintenance/guess",
+			setup: func(mockHash *mockshash.Hash, mockLog *mockslog.Log) {
+				mockHash.EXPECT().Check("guess", "hashed").Return(false).Once()
+			},
+			expectCode:  http.StatusServiceUnavailable,
+			expectRetry: "60",
+		},
+		{
+			name:        "secret query",
+			maintenance: `{"reason":"upgrading","status":503,"secret":"hashed"}`,
+			path:        "/users?secret=secret",
+			setup: func(mockHash *mockshash.Hash, mockLog *mockslog.Log) {
+				mockHash.EXPECT().Check("secret", "hashed").Return(true).Once()
+			},
+			expectCode: http.StatusOK,
+			expectBody: "ok",
+			assert: func(t *testing.T, resp *http.Response) {
+				assert.Len(t, resp.Cookies(), 1)
+			},
+		},
+		{
+			name:        "forged cookie",
+			maintenance: `{"reason":"upgrading","status":503,"secret":"hashed"}`,
+			path:        "/users",
+			headers:     map[string]string{"Cookie": "goravel_maintenance=9999999999.forged"},
+			expectCode:  http.StatusServiceUnavailable,
+			expectRetry: "60",
+		},
+		{
+			name:        "redirect",
+			maintenance: `{"redirect":"/status","status":503}`,
+			path:        "/users",
+			expectCode:  http.StatusTemporaryRedirect,
+			assert: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, "/status", resp.Header.Get("Location"))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockHash := newMaintenanceTestApp(t, test.maintenance, configKey, test.settings)
+			mockLog := mockslog.NewLog(t)
+			LogFacade = mockLog
+			if test.setup != nil {
+				test.setup(mockHash, mockLog)
+			}
+
+			app := newMiddlewareTestApp(nil, CheckForMaintenanceMode(MaintenanceConfig{ConfigKey: configKey}))
+			req := httptest.NewRequest("GET", test.path, nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectCode, resp.StatusCode)
+			assert.Equal(t, test.expectRetry, resp.Header.Get(HeaderRetryAfter))
+			if test.expectBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectBody, string(body))
+			}
+			if test.assert != nil {
+				test.assert(t, resp)
+			}
+		})
+	}
+}
+
+func TestCheckForMaintenanceModeBypassCookie(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	maintenance := `{"reason":"upgrading","status":503,"secret":"hashed"}`
+	mockHash := newMaintenanceTestApp(t, maintenance, "", nil)
+	mockHash.EXPECT().Check("secret", "hashed").Return(true).Once()
+	app := newMiddlewareTestApp(nil, CheckForMaintenanceMode(MaintenanceConfig{AllowedIPs: []string{"10.0.0.1"}}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/maintenance/secret", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+	cookie := resp.Cookies()[0]
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.AddCookie(cookie)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The cookie of a previous secret is rejected.
+	newMaintenanceTestApp(t, `{"reason":"upgrading","status":503,"secret":"rehashed"}`, "", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	assert.Panics(t, func() {
+		CheckForMaintenanceMode(MaintenanceConfig{AllowedIPs: []string{"invalid"}})
+	})
+}
+
+func TestCheckForMaintenanceModeAllowedIPsOfConfig(t *testing.T) {
+	mockLog := mockslog.NewLog(t)
+	LogFacade = mockLog
+	mockLog.EXPECT().Error(mock.Anything).Once()
+
+	m := CheckForMaintenanceMode(MaintenanceConfig{
+		AllowedIPs: []string{"10.0.0.0/8"},
+		ConfigKey:  "http.drivers.fiber.maintenance",
+	}).(*maintenanceMiddleware)
+
+	// The invalid IP is reported once, the list is parsed again only when the config changes.
+	allowedIPs := m.loadAllowedIPs([]string{"invalid", "192.168.0.1"})
+	assert.Len(t, allowedIPs, 2)
+	assert.Equal(t, allowedIPs, m.loadAllowedIPs([]string{"invalid", "192.168.0.1"}))
+	assert.Len(t, m.loadAllowedIPs([]string{"127.0.0.1", "192.168.0.1"}), 3)
+	assert.Len(t, m.loadAllowedIPs(nil), 1)
+	assert.Len(t, m.allowedIPs, 1)
+}
+
+// newMaintenanceTestApp sets App with the facades the middleware reads the maintenance state and the settings of
+// the config key from, the application is down if the maintenance content isn't empty.
+func newMaintenanceTestApp(t *testing.T, maintenance, configKey string, settings map[string]any) *mockshash.Hash {
+	mockApp := mocksfoundation.NewApplication(t)
+	mockConfig := mocksconfig.NewConfig(t)
+	mockStorage := mocksfilesystem.NewStorage(t)
+	mockHash := mockshash.NewHash(t)
+	mockApp.EXPECT().MakeConfig().Return(mockConfig).Maybe()
+	mockApp.EXPECT().MakeCache().Return(mockscache.NewCache(t)).Maybe()
+	mockApp.EXPECT().MakeStorage().Return(mockStorage).Maybe()
+	mockApp.EXPECT().MakeHash().Return(mockHash).Maybe()
+
+	mockConfig.EXPECT().GetString("app.maintenance.driver", "file").Return("file").Maybe()
+	mockConfig.EXPECT().GetString("app.key").Return("goravel-app-key").Maybe()
+	mockStorage.EXPECT().Exists("framework/maintenance.json").Return(maintenance != "").Maybe()
+	mockStorage.EXPECT().GetBytes("framework/maintenance.json").Return([]byte(maintenance), nil).Maybe()
+	if configKey != "" {
+		mockConfig.EXPECT().Get(configKey + ".allowed_ips").Return(settings["allowed_ips"]).Maybe()
+		mockConfig.EXPECT().Get(configKey + ".except").Return(settings["except"]).Maybe()
+		retryAfter, _ := settings["retry_after"].(int)
+		mockConfig.EXPECT().GetInt(configKey+".retry_after", 0).Return(retryAfter).Maybe()
+	}
+	App = mockApp
+
+	return mockHash
+}
@@ -0,0 +1,55 @@
+package fiber
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// HeaderMethodOverride carries the overridden method of a POST request sent by a client which can't send it.
+const HeaderMethodOverride = "X-HTTP-Method-Override"
+
+// methodOverrideField is the form field carrying the overridden method, e.g. <input type="hidden" name="_method" value="PUT">.
+const methodOverrideField = "_method"
+
+var methodOverrideMethods = []string{fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete}
+
+// methodOverride rewrites the method of a POST request from X-HTTP-Method-Override or the _method form field,
+// so HTML forms reach the PUT, PATCH and DELETE routes of Resource. It's enabled by
+// http.drivers.fiber.method_override and registered before the other handlers of the app, because the route
+// is matched by the method.
+func methodOverride(c fiber.Ctx) error {
+	if c.Method() != fiber.MethodPost {
+		return c.Next()
+	}
+
+	method := c.Get(HeaderMethodOverride)
+	if method == "" {
+		method = methodOverrideFormValue(c)
+	}
+
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if slices.Contains(methodOverrideMethods, method) {
+		c.Method(method)
+	}
+
+	return c.Next()
+}
+
+// methodOverrideFormValue reads the field from the form body only, the query can't change the method of a form.
+func methodOverrideFormValue(c fiber.Ctx) string {
+	contentType := strings.ToLower(string(c.Request().Header.ContentType()))
+	switch {
+	case strings.HasPrefix(contentType, fiber.MIMEApplicationForm):
+		return string(c.RequestCtx().PostArgs().Peek(methodOverrideField))
+	case strings.HasPrefix(contentType, fiber.MIMEMultipartForm):
+		form, err := c.MultipartForm()
+		if err != nil || len(form.Value[methodOverrideField]) == 0 {
+			return ""
+		}
+		return form.Value[methodOverrideField][0]
+	default:
+		return ""
+	}
+}
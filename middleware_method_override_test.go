@@ -0,0 +1,180 @@
+package fiber
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mocksconfig "github.com/goravel/framework/mocks/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethodOverride(t *testing.T) {
+	routes = make(map[string]map[string]contractshttp.Info)
+
+	app := fiber.New()
+	app.Use(methodOverride)
+	group := &Group{instance: app, hooks: &Hooks{}}
+	respond := func(ctx contractshttp.Context) contractshttp.Response {
+		info := ctx.Request().Info()
+		return ctx.Response().String(http.StatusOK, ctx.Request().Method()+" "+info.Name)
+	}
+	group.Post("/users/{id}", respond).Name("users.store")
+	group.Put("/users/{id}", respond).Name("users.update")
+	group.Delete("/users/{id}", respond).Name("users.destroy")
+
+	multipartBody := func(method string) (io.Reader, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.WriteField(methodOverrideField, method))
+		require.NoError(t, writer.Close())
+		return &body, writer.FormDataContentType()
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       func() (io.Reader, string)
+		header     string
+		expectBody string
+	}{
+		{
+			name:   "form field",
+			method: "POST",
+			body: func() (io.Reader, string) {
+				return strings.NewReader("_method=put&name=goravel"), fiber.MIMEApplicationForm
+			},
+			expectBody: "PUT users.update",
+		},
+		{
+			name:       "multipart form field",
+			method:     "POST",
+			body:       func() (io.Reader, string) { return multipartBody("delete") },
+			expectBody: "DELETE users.destroy",
+		},
+		{
+			name:       "header",
+			method:     "POST",
+			header:     "PUT",
+			expectBody: "PUT users.update",
+		},
+		{
+			name:       "the header takes precedence",
+			method:     "POST",
+			header:     "DELETE",
+			body:       func() (io.Reader, string) { return strings.NewReader("_method=PUT"), fiber.MIMEApplicationForm },
+			expectBody: "DELETE users.destroy",
+		},
+		{
+			name:       "unsupported method",
+			method:     "POST",
+			header:     "GET",
+			expectBody: "POST users.store",
+		},
+		{
+			name:       "query is ignored",
+			method:     "POST",
+			path:       "/users/1?_method=PUT",
+			expectBody: "POST users.store",
+		},
+		{
+			name:       "only POST is overridden",
+			method:     "PUT",
+			header:     "DELETE",
+			expectBody: "PUT users.update",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := test.path
+			if path == "" {
+				path = "/users/1"
+			}
+			var (
+				body        io.Reader
+				contentType string
+			)
+			if test.body != nil {
+				body, contentType = test.body()
+			}
+
+			req := httptest.NewRequest(test.method, path, body)
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			if test.header != "" {
+				req.Header.Set(HeaderMethodOverride, test.header)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, test.expectBody, string(content))
+		})
+	}
+}
+
+func TestMethodOverrideWithRoute(t *testing.T) {
+	routes = make(map[string]map[string]contractshttp.Info)
+	defer func() {
+		ConfigFacade = nil
+	}()
+
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(3).Once()
+	mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
+	mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("").Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+	mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("app.debug", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(true).Once()
+	// The Cors global middleware is skipped without cors.paths.
+	mockConfig.EXPECT().Get("cors.paths").Return(nil).Once()
+	ConfigFacade = mockConfig
+
+	route, err := NewRoute(mockConfig, map[string]any{"driver": "fiber"})
+	require.NoError(t, err)
+	route.Put("/users/{id}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, ctx.Request().Method()+" "+ctx.Request().Route("id"))
+	})
+
+	req := httptest.NewRequest("POST", "/users/1", strings.NewReader("_method=PUT"))
+	req.Header.Set("Content-Type", fiber.MIMEApplicationForm)
+	resp, err := route.Test(req)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "PUT 1", string(content))
+}
@@ -0,0 +1,103 @@
+package fiber
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricsUnmatchedRoute labels the requests which don't match a route, so scanners can't create a series per path.
+const metricsUnmatchedRoute = "unmatched"
+
+type metricsMiddleware struct {
+	registry *metricsRegistry
+}
+
+func (m *metricsMiddleware) Signature() string {
+	return "goravel:metrics"
+}
+
+func (m *metricsMiddleware) Handle(ctx contractshttp.Context) {
+	request := ctx.Request()
+	method := request.Method()
+
+	m.registry.addInFlight(method, 1)
+	defer m.registry.addInFlight(method, -1)
+
+	start := time.Now()
+	request.Next()
+	latency := time.Since(start)
+
+	c := ctx.(*Context).Instance()
+	if invalidFiber(c) {
+		return
+	}
+
+	response := c.Response()
+	status := response.StatusCode()
+	m.registry.observe(metricsLabels{
+		method: method,
+		route:  metricsRoute(c, request),
+		status: strconv.Itoa(status/100) + "xx",
+	}, latency.Seconds(), metricsBodySize(c.Request().IsBodyStream(), c.Request().Header.ContentLength(), c.Request().Body),
+		metricsBodySize(response.IsBodyStream(), response.Header.ContentLength(), response.Body))
+}
+
+// Metrics creates middleware to collect the request count, latency, size and in-flight metrics, which are exposed
+// by MetricsHandler. It's registered globally by http.drivers.fiber.metrics.enabled, so it's only needed to
+// collect the metrics of some routes.
+func Metrics() contractshttp.Middleware {
+	return &metricsMiddleware{registry: httpMetrics}
+}
+
+// MetricsHandler exposes the metrics collected by the Metrics middleware in the Prometheus text exposition format,
+// it's used to serve them from a custom route, e.g. behind an authentication middleware.
+func MetricsHandler() contractshttp.HandlerFunc {
+	return func(ctx contractshttp.Context) contractshttp.Response {
+		var buffer bytes.Buffer
+		_ = httpMetrics.write(&buffer)
+
+		return ctx.Response().Data(contractshttp.StatusOK, metricsContentType, buffer.Bytes())
+	}
+}
+
+// metricsEndpoint serves the metrics on the path before the global middleware, so they don't apply to the scrapes.
+func metricsEndpoint(path string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if c.Path() != path || (c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead) {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderContentType, metricsContentType)
+
+		return httpMetrics.write(c.Response().BodyWriter())
+	}
+}
+
+// metricsRoute is the route name or the path template resolved after the handlers ran, never the raw path.
+func metricsRoute(c fiber.Ctx, request contractshttp.ContextRequest) string {
+	route := matchedRoute(c)
+	if route == "" {
+		return metricsUnmatchedRoute
+	}
+
+	if name := request.Name(); name != "" {
+		return name
+	}
+
+	return route
+}
+
+// metricsBodySize reads the size of a stream from Content-Length only, reading the stream would consume it.
+func metricsBodySize(stream bool, contentLength int, body func() []byte) int {
+	if !stream {
+		return len(body())
+	}
+
+	return max(contentLength, 0)
+}
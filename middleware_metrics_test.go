@@ -0,0 +1,112 @@
+package fiber
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := newMetricsRegistry()
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		switch ctx.Request().Path() {
+		case "/missing":
+			return ctx.Response().String(http.StatusNotFound, "not found")
+		case "/error":
+			return ctx.Response().String(http.StatusInternalServerError, "error")
+		default:
+			return ctx.Response().String(http.StatusOK, "ok")
+		}
+	}, &metricsMiddleware{registry: registry})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/users/1", nil),
+		httptest.NewRequest("GET", "/users/2", nil),
+		httptest.NewRequest("POST", "/error", strings.NewReader("goravel")),
+		httptest.NewRequest("GET", "/missing", nil),
+	} {
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, registry.write(&buffer))
+	metrics := buffer.String()
+
+	// The raw paths are folded into the path template.
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/*",status="2xx"} 2`)
+	assert.Contains(t, metrics, `http_requests_total{method="POST",route="/*",status="5xx"} 1`)
+	// The route matched, though it responded with 404.
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/*",status="4xx"} 1`)
+	assert.NotContains(t, metrics, "/users/1")
+	assert.Contains(t, metrics, `http_requests_in_flight{method="GET"} 0`)
+	assert.Contains(t, metrics, `http_request_duration_seconds_count{method="GET",route="/*",status="2xx"} 2`)
+	assert.Contains(t, metrics, `http_request_size_bytes_bucket{method="POST",route="/*",le="100"} 1`)
+	assert.Contains(t, metrics, `http_request_size_bytes_sum{method="POST",route="/*"} 7`)
+	assert.Contains(t, metrics, `http_response_size_bytes_sum{method="GET",route="/*",status="2xx"} 4`)
+	assert.Contains(t, metrics, `http_response_size_bytes_bucket{method="GET",route="/*",status="2xx",le="+Inf"} 2`)
+	assert.Equal(t, "goravel:metrics", Metrics().Signature())
+}
+
+func TestMetricsUnmatchedRoute(t *testing.T) {
+	routes = make(map[string]map[string]contractshttp.Info)
+	NewAction(contractshttp.MethodGet, "/", "home").Name("home")
+	defer func() {
+		routes = make(map[string]map[string]contractshttp.Info)
+	}()
+
+	registry := newMetricsRegistry()
+	app := newGlobalMiddlewareTestApp(nil, &metricsMiddleware{registry: registry})
+
+	for _, path := range []string{"/", "/missing"} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, registry.write(&buffer))
+	metrics := buffer.String()
+
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="home",status="2xx"} 1`)
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="unmatched",status="4xx"} 1`)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	app := fiber.New()
+	app.Use(metricsEndpoint("/metrics"))
+	app.Use(func(c fiber.Ctx) error {
+		c.Set("X-Global", "true")
+		return c.Next()
+	})
+	app.Get("/*", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, metricsContentType, resp.Header.Get("Content-Type"))
+	assert.Empty(t, resp.Header.Get("X-Global"))
+	assert.Contains(t, string(body), "# TYPE http_requests_total counter")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/users", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "true", resp.Header.Get("X-Global"))
+}
+
+func TestQuoteMetricsLabel(t *testing.T) {
+	assert.Equal(t, `"users.show"`, quoteMetricsLabel("users.show"))
+	assert.Equal(t, `"a\\b\"c\nd"`, quoteMetricsLabel("a\\b\"c\nd"))
+}
@@ -0,0 +1,97 @@
+package fiber
+
+import (
+	"context"
+
+	"github.com/gofiber/utils/v2"
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds the inbound ID, it's written to every log entry of the request.
+const maxRequestIDLength = 128
+
+type requestIDKeyType string
+
+// requestIDKey is a string type, so the log entries label the value as request_id.
+const requestIDKey requestIDKeyType = "request_id"
+
+// RequestIDConfig configures the RequestID middleware, the zero value uses the defaults.
+type RequestIDConfig struct {
+	// Header is read from requests of trusted proxies and echoed in the response.
+	// Default: X-Request-ID
+	Header string
+	// Generator creates the ID when the request doesn't carry a trusted one.
+	// Default: UUID v4
+	Generator func() string
+}
+
+type requestIDMiddleware struct {
+	config RequestIDConfig
+}
+
+func (m *requestIDMiddleware) Signature() string {
+	return "goravel:request_id"
+}
+
+func (m *requestIDMiddleware) Handle(ctx contractshttp.Context) {
+	c := ctx.(*Context).Instance()
+
+	var id string
+	// The inbound header can be forged by any client, so it's only honored from the proxies trusted by
+	// http.drivers.fiber.trusted_proxies.
+	if c.IsProxyTrusted() {
+		id = c.Get(m.config.Header)
+		if !validRequestID(id) {
+			id = ""
+		}
+	}
+	if id == "" {
+		id = m.config.Generator()
+	}
+
+	ctx.WithValue(requestIDKey, id)
+	c.Set(m.config.Header, id)
+
+	ctx.Request().Next()
+}
+
+// RequestID creates middleware to assign an ID to every request. The ID is stored in the context values, so it's
+// attached to the entries of LogFacade.WithContext(ctx) automatically, and it's echoed in the response header.
+func RequestID(config ...RequestIDConfig) contractshttp.Middleware {
+	var cfg RequestIDConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Header == "" {
+		cfg.Header = HeaderRequestID
+	}
+	if cfg.Generator == nil {
+		cfg.Generator = utils.UUIDv4
+	}
+
+	return &requestIDMiddleware{config: cfg}
+}
+
+// GetRequestID returns the ID assigned by the RequestID middleware, it accepts both the http context and
+// the context.Context derived from it.
+func GetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+
+	return id
+}
+
+// validRequestID rejects the IDs which could break the log lines or the response header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,110 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	generator := func() string {
+		return "generated"
+	}
+
+	tests := []struct {
+		name         string
+		config       RequestIDConfig
+		trustProxy   bool
+		headers      map[string]string
+		expectHeader string
+		expectID     string
+	}{
+		{
+			name:         "generate an id",
+			expectHeader: HeaderRequestID,
+			expectID:     "generated",
+		},
+		{
+			name:         "ignore the inbound id from an untrusted client",
+			headers:      map[string]string{HeaderRequestID: "inbound"},
+			expectHeader: HeaderRequestID,
+			expectID:     "generated",
+		},
+		{
+			name:         "take the inbound id from a trusted proxy",
+			trustProxy:   true,
+			headers:      map[string]string{HeaderRequestID: "inbound"},
+			expectHeader: HeaderRequestID,
+			expectID:     "inbound",
+		},
+		{
+			name:         "reject an invalid inbound id",
+			trustProxy:   true,
+			headers:      map[string]string{HeaderRequestID: "in bound"},
+			expectHeader: HeaderRequestID,
+			expectID:     "generated",
+		},
+		{
+			name:         "custom header",
+			config:       RequestIDConfig{Header: "X-Correlation-ID"},
+			trustProxy:   true,
+			headers:      map[string]string{"X-Correlation-ID": "inbound"},
+			expectHeader: "X-Correlation-ID",
+			expectID:     "inbound",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.Generator = generator
+
+			var handlerID, contextID string
+			app := newTrustedMiddlewareTestApp(test.trustProxy, func(ctx contractshttp.Context) contractshttp.Response {
+				handlerID = GetRequestID(ctx)
+				contextID = GetRequestID(ctx.Context())
+				return ctx.Response().String(http.StatusOK, "ok")
+			}, RequestID(test.config))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, test.expectID, resp.Header.Get(test.expectHeader))
+			assert.Equal(t, test.expectID, handlerID)
+			assert.Equal(t, test.expectID, contextID)
+		})
+	}
+}
+
+func TestRequestIDWithRecover(t *testing.T) {
+	mockLog := mockslog.NewLog(t)
+	mockLog.EXPECT().WithContext(mock.MatchedBy(func(ctx context.Context) bool {
+		return GetRequestID(ctx) == "generated"
+	})).Return(mockLog).Once()
+	mockLog.EXPECT().Request(mock.Anything).Return(mockLog).Once()
+	mockLog.EXPECT().Error("test panic").Once()
+	LogFacade = mockLog
+
+	// The recover middleware wraps the request ID one like the global handlers of Route.
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		panic("test panic")
+	}, &recoverMiddleware{}, RequestID(RequestIDConfig{Generator: func() string {
+		return "generated"
+	}}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "generated", resp.Header.Get(HeaderRequestID))
+}
@@ -0,0 +1,448 @@
+package fiber
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/json"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	HeaderXCache = "X-Cache"
+
+	ResponseCacheHit   = "HIT"
+	ResponseCacheMiss  = "MISS"
+	ResponseCacheStale = "STALE"
+)
+
+// ResponseCacheConfig configures the ResponseCache middleware, the zero value uses the defaults.
+type ResponseCacheConfig struct {
+	// TTL is how long a response is fresh, max-age or s-maxage of the handler's Cache-Control takes precedence.
+	// Default: 1 minute
+	TTL time.Duration
+	// StaleWhileRevalidate is how long an expired response is still served while it's refreshed in the background,
+	// stale-while-revalidate of the handler's Cache-Control takes precedence.
+	StaleWhileRevalidate time.Duration
+	// Vary are the request headers which are part of the cache key, e.g. Accept-Language. A response whose Vary
+	// header, set by the handlers or the middleware after this one, names another header isn't cached, e.g. add
+	// Accept-Encoding if Compress runs after this middleware.
+	Vary []string
+	// Tags are attached to every cached response, the handlers can add more via AddResponseCacheTags.
+	Tags []string
+	// Store Default: a memory store shared by all ResponseCache middleware.
+	Store Store
+}
+
+// responseCacheTagTTL is how long a tag version is kept, the responses stored with an expired version are missed.
+const responseCacheTagTTL = 24 * time.Hour
+
+var defaultResponseCacheStore = NewMemoryStore()
+
+var responseCacheNow = time.Now
+
+// perResponseHeaders are generated for every response, or owned by the middleware which set them for every request,
+// e.g. the request ID and the CSP nonce, so they aren't stored to be replayed.
+var perResponseHeaders = []string{
+	fiber.HeaderAge,
+	fiber.HeaderConnection,
+	fiber.HeaderContentLength,
+	fiber.HeaderDate,
+	fiber.HeaderTransferEncoding,
+	fiber.HeaderContentSecurityPolicy,
+	fiber.HeaderContentSecurityPolicyReportOnly,
+	HeaderRequestID,
+	HeaderXCache,
+}
+
+type responseCacheTagsKeyType struct{}
+type responseCacheRevalidateKeyType struct{}
+
+var (
+	responseCacheTagsKey       = responseCacheTagsKeyType{}
+	responseCacheRevalidateKey = responseCacheRevalidateKeyType{}
+)
+
+type cachedResponse struct {
+	Status  int               `json:"status"`
+	Headers [][2]string       `json:"headers"`
+	Body    []byte            `json:"body"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	// The times are unix nanoseconds.
+	StoredAt   int64 `json:"stored_at"`
+	FreshUntil int64 `json:"fresh_until"`
+	StaleUntil int64 `json:"stale_until"`
+}
+
+type responseCacheMiddleware struct {
+	config     ResponseCacheConfig
+	refreshing sync.Map
+}
+
+func (m *responseCacheMiddleware) Signature() string {
+	return "goravel:response_cache"
+}
+
+func (m *responseCacheMiddleware) Handle(ctx contractshttp.Context) {
+	c := ctx.(*Context).Instance()
+
+	method := c.Method()
+	if method != fiber.MethodGet && method != fiber.MethodHead {
+		ctx.Request().Next()
+		return
+	}
+
+	key := m.key(c)
+
+	if origin, _ := c.RequestCtx().UserValue(responseCacheRevalidateKey).(*responseCacheMiddleware); origin == m {
+		// The background refresh of a stale response skips the lookup, the middleware after this one run again.
+		c.RequestCtx().RemoveUserValue(responseCacheRevalidateKey)
+	} else {
+		cached, err := m.lookup(ctx, key)
+		if err != nil {
+			LogFacade.Error(fmt.Errorf("response cache failed to get %s: %w", key, err))
+		} else if cached != nil {
+			now := responseCacheNow().UnixNano()
+			if now < cached.FreshUntil {
+				writeCachedResponse(c, cached, ResponseCacheHit)
+				return
+			}
+			if now < cached.StaleUntil {
+				writeCachedResponse(c, cached, ResponseCacheStale)
+				m.revalidate(c, key)
+				return
+			}
+		}
+	}
+
+	// The Vary headers of the middleware before this one are sent with every response, they aren't part of the key.
+	varyBefore := c.GetRespHeader(fiber.HeaderVary)
+	ctx.Request().Next()
+
+	if invalidFiber(c) {
+		return
+	}
+
+	// A HEAD response has no body, so it's served from the GET response but never stored.
+	if method == fiber.MethodGet {
+		if err := m.store(ctx, c, key, varyBefore); err != nil {
+			LogFacade.Error(fmt.Errorf("response cache failed to put %s: %w", key, err))
+		}
+	}
+	c.Set(HeaderXCache, ResponseCacheMiss)
+}
+
+// key is built from the host, the path, the sorted query and the Vary headers.
+func (m *responseCacheMiddleware) key(c fiber.Ctx) string {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	rawQuery := query.Encode()
+	if err != nil {
+		rawQuery = string(c.Request().URI().QueryString())
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Hostname() + "\n" + c.Path() + "\n" + rawQuery))
+	for _, header := range m.config.Vary {
+		hash.Write([]byte("\n" + header + ":" + c.Get(header)))
+	}
+
+	return "response_cache:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+func (m *responseCacheMiddleware) lookup(ctx context.Context, key string) (*cachedResponse, error) {
+	value, err := m.config.Store.Get(ctx, key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(value, &cached); err != nil {
+		return nil, err
+	}
+
+	for tag, version := range cached.Tags {
+		current, err := m.config.Store.Get(ctx, responseCacheTagKey(tag))
+		if err != nil {
+			return nil, err
+		}
+		// The tag has been invalidated, expired or evicted after the response was stored.
+		if string(current) != version {
+			return nil, nil
+		}
+	}
+
+	return &cached, nil
+}
+
+func (m *responseCacheMiddleware) store(ctx contractshttp.Context, c fiber.Ctx, key, varyBefore string) error {
+	response := c.Response()
+	if response.StatusCode() != fiber.StatusOK || len(response.Header.Peek(fiber.HeaderSetCookie)) > 0 {
+		return nil
+	}
+	// The key only covers the Vary headers of the config, e.g. a gzip body must not be served to the clients
+	// which don't accept it.
+	for token := range strings.SplitSeq(c.GetRespHeader(fiber.HeaderVary), ",") {
+		token = strings.TrimSpace(token)
+		if token == "" || hasHeaderToken(varyBefore, token) {
+			continue
+		}
+		if token == "*" || !slices.ContainsFunc(m.config.Vary, func(header string) bool {
+			return strings.EqualFold(header, token)
+		}) {
+			return nil
+		}
+	}
+	// A stream, e.g. of the File and Stream responses, would be read into memory, and an event stream may never end.
+	if response.IsBodyStream() || strings.HasPrefix(string(response.Header.ContentType()), fiber.MIMETextEventStream) {
+		return nil
+	}
+
+	cacheControl := c.GetRespHeader(fiber.HeaderCacheControl)
+	// The responses to the requests with credentials are personal unless the handler marks them as shared.
+	credentials := len(c.Request().Header.Peek(fiber.HeaderAuthorization)) > 0 || len(c.Request().Header.Peek(fiber.HeaderCookie)) > 0
+	if credentials && !sharedCacheControl(cacheControl) {
+		return nil
+	}
+
+	ttl, staleWhileRevalidate, ok := m.lifetime(cacheControl)
+	if !ok {
+		return nil
+	}
+
+	cached := &cachedResponse{
+		Status: response.StatusCode(),
+		Body:   slices.Clone(response.Body()),
+	}
+	for key, value := range response.Header.All() {
+		name := string(key)
+		if !isPerResponseHeader(name) {
+			cached.Headers = append(cached.Headers, [2]string{name, string(value)})
+		}
+	}
+
+	tags := slices.Concat(m.config.Tags, responseCacheTags(c))
+	if len(tags) > 0 {
+		cached.Tags = make(map[string]string, len(tags))
+		for _, tag := range tags {
+			version, err := m.tagVersion(ctx, tag)
+			if err != nil || version == "" {
+				return err
+			}
+			cached.Tags[tag] = version
+		}
+	}
+
+	now := responseCacheNow()
+	cached.StoredAt = now.UnixNano()
+	cached.FreshUntil = now.Add(ttl).UnixNano()
+	cached.StaleUntil = now.Add(ttl + staleWhileRevalidate).UnixNano()
+
+	value, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	return m.config.Store.Put(ctx, key, value, ttl+staleWhileRevalidate)
+}
+
+// tagVersion returns the current version of the tag, a new one is created if it doesn't exist, so a response is
+// never stored with a version the tag gets again once its state expires or is evicted.
+func (m *responseCacheMiddleware) tagVersion(ctx context.Context, tag string) (string, error) {
+	key := responseCacheTagKey(tag)
+	if _, err := m.config.Store.Add(ctx, key, newResponseCacheTagVersion(), responseCacheTagTTL); err != nil {
+		return "", err
+	}
+
+	version, err := m.config.Store.Get(ctx, key)
+
+	return string(version), err
+}
+
+// lifetime returns the TTL and the stale-while-revalidate of the response, ok is false if it can't be cached.
+func (m *responseCacheMiddleware) lifetime(cacheControl string) (ttl, staleWhileRevalidate time.Duration, ok bool) {
+	ttl, staleWhileRevalidate = m.config.TTL, m.config.StaleWhileRevalidate
+
+	var sharedMaxAge bool
+	for directive := range strings.SplitSeq(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache", "private":
+			return 0, 0, false
+		case "max-age":
+			if err == nil && !sharedMaxAge {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		case "s-maxage":
+			if err == nil {
+				ttl = time.Duration(seconds) * time.Second
+				sharedMaxAge = true
+			}
+		case "stale-while-revalidate":
+			if err == nil {
+				staleWhileRevalidate = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return ttl, staleWhileRevalidate, ttl > 0
+}
+
+// sharedCacheControl reports whether the response may be shared between the clients via public or s-maxage.
+func sharedCacheControl(cacheControl string) bool {
+	for directive := range strings.SplitSeq(cacheControl, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if strings.EqualFold(name, "public") || strings.EqualFold(name, "s-maxage") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// revalidate refreshes the response in the background by running the request through the app again, the middleware
+// before this one are skipped since they already ran for the request which got the stale response, e.g. the throttle
+// and the access log. Only one refresh runs for a key at a time.
+func (m *responseCacheMiddleware) revalidate(c fiber.Ctx, key string) {
+	if _, refreshing := m.refreshing.LoadOrStore(key, struct{}{}); refreshing {
+		return
+	}
+
+	var request fasthttp.Request
+	c.Request().CopyTo(&request)
+	request.Header.SetMethod(fiber.MethodGet)
+	remoteAddr := c.RequestCtx().RemoteAddr()
+	handler := c.App().Handler()
+
+	go func() {
+		defer m.refreshing.Delete(key)
+
+		var requestCtx fasthttp.RequestCtx
+		requestCtx.Init(&request, remoteAddr, nil)
+		requestCtx.SetUserValue(responseCacheRevalidateKey, m)
+		handler(&requestCtx)
+	}()
+}
+
+// skipBeforeRevalidation reports whether the middleware runs before the ResponseCache middleware which refreshes
+// the response of the request in the background, so it's skipped.
+func skipBeforeRevalidation(c fiber.Ctx, middleware contractshttp.Middleware) bool {
+	origin, ok := c.RequestCtx().UserValue(responseCacheRevalidateKey).(*responseCacheMiddleware)
+
+	return ok && origin != middleware
+}
+
+// ResponseCache creates middleware to cache the complete GET responses, including status, headers and body, except the
+// streamed ones, e.g. File and Stream. Only 200 responses without cookies are cached, the responses to the requests
+// with Authorization or Cookie only if their Cache-Control has public or s-maxage. The handlers can opt out via Cache-Control no-store,
+// no-cache or private. The responses can be invalidated by tags via InvalidateResponseCache.
+func ResponseCache(config ...ResponseCacheConfig) contractshttp.Middleware {
+	var cfg ResponseCacheConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Minute
+	}
+	if cfg.StaleWhileRevalidate < 0 {
+		cfg.StaleWhileRevalidate = 0
+	}
+	if cfg.Store == nil {
+		cfg.Store = defaultResponseCacheStore
+	}
+
+	return &responseCacheMiddleware{config: cfg}
+}
+
+// AddResponseCacheTags attaches tags to the response of the request if it's cached by the ResponseCache middleware.
+func AddResponseCacheTags(ctx contractshttp.Context, tags ...string) {
+	c := ctx.(*Context).Instance()
+	c.Locals(responseCacheTagsKey, slices.Concat(responseCacheTags(c), tags))
+}
+
+// InvalidateResponseCache invalidates the cached responses with any of the tags, the default store is used if store is nil.
+func InvalidateResponseCache(ctx context.Context, store Store, tags ...string) error {
+	if store == nil {
+		store = defaultResponseCacheStore
+	}
+
+	var errs []error
+	for _, tag := range tags {
+		if err := store.Put(ctx, responseCacheTagKey(tag), newResponseCacheTagVersion(), responseCacheTagTTL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func responseCacheTags(c fiber.Ctx) []string {
+	tags, _ := c.Locals(responseCacheTagsKey).([]string)
+
+	return tags
+}
+
+func newResponseCacheTagVersion() []byte {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return []byte(hex.EncodeToString(b))
+}
+
+func responseCacheTagKey(tag string) string {
+	return "response_cache:tag:" + tag
+}
+
+func writeCachedResponse(c fiber.Ctx, cached *cachedResponse, status string) {
+	writeStoredResponse(c, cached.Status, cached.Headers, cached.Body)
+
+	age := max((responseCacheNow().UnixNano()-cached.StoredAt)/int64(time.Second), 0)
+	c.Set(fiber.HeaderAge, strconv.FormatInt(age, 10))
+	c.Set(HeaderXCache, status)
+}
+
+func isPerResponseHeader(name string) bool {
+	return slices.ContainsFunc(perResponseHeaders, func(header string) bool {
+		return strings.EqualFold(header, name)
+	})
+}
+
+// writeStoredResponse replaces the response with a stored one. The stored headers replace the ones set by the
+// middleware before, except Vary which is merged.
+func writeStoredResponse(c fiber.Ctx, status int, headers [][2]string, body []byte) {
+	response := c.Response()
+	response.SetStatusCode(status)
+	replaced := make(map[string]bool)
+	for _, header := range headers {
+		name := strings.ToLower(header[0])
+		if name == strings.ToLower(fiber.HeaderVary) {
+			for token := range strings.SplitSeq(header[1], ",") {
+				if token = strings.TrimSpace(token); token != "" {
+					appendVary(c, token)
+				}
+			}
+			continue
+		}
+		// A header can be stored several times, e.g. Link, only the values set before are removed.
+		if !replaced[name] {
+			response.Header.Del(header[0])
+			replaced[name] = true
+		}
+		response.Header.Add(header[0], header[1])
+	}
+	response.SetBodyRaw(body)
+}
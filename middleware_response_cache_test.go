@@ -0,0 +1,481 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	defer func() {
+		responseCacheNow = time.Now
+	}()
+
+	file := filepath.Join(t.TempDir(), "goravel.txt")
+	require.NoError(t, os.WriteFile(file, []byte("file"), 0644))
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	responseCacheNow = func() time.Time {
+		return now
+	}
+
+	type request struct {
+		method string
+		target string
+		header map[string]string
+		// advance moves the clock of the middleware before the request
+		advance      time.Duration
+		invalidate   []string
+		expectCache  string
+		expectBody   string
+		expectCalled int32
+	}
+
+	tests := []struct {
+		name              string
+		config            ResponseCacheConfig
+		handler           func(called int32, ctx contractshttp.Context) contractshttp.Response
+		requests          []request
+		expectContentType string
+	}{
+		{
+			name: "string response",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("hello %d", called))
+			},
+			requests: []request{
+				{expectCache: ResponseCacheMiss, expectBody: "hello 1", expectCalled: 1},
+				{advance: 59 * time.Second, expectCache: ResponseCacheHit, expectBody: "hello 1", expectCalled: 1},
+				{method: "HEAD", expectCache: ResponseCacheHit, expectCalled: 1},
+				{advance: time.Second, expectCache: ResponseCacheMiss, expectBody: "hello 2", expectCalled: 2},
+				{method: "POST", expectCache: "", expectBody: "hello 3", expectCalled: 3},
+			},
+			expectContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name: "json response",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().Json(http.StatusOK, map[string]int32{"called": called})
+			},
+			requests: []request{
+				{expectCache: ResponseCacheMiss, expectBody: `{"called":1}`, expectCalled: 1},
+				{expectCache: ResponseCacheHit, expectBody: `{"called":1}`, expectCalled: 1},
+			},
+			expectContentType: "application/json; charset=utf-8",
+		},
+		{
+			name: "data response",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().Data(http.StatusOK, "text/csv", []byte("a,b"))
+			},
+			requests: []request{
+				{expectCache: ResponseCacheMiss, expectBody: "a,b", expectCalled: 1},
+				{expectCache: ResponseCacheHit, expectBody: "a,b", expectCalled: 1},
+			},
+			expectContentType: "text/csv",
+		},
+		{
+			name: "file response isn't cached",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().File(file)
+			},
+			requests: []request{
+				{expectCache: ResponseCacheMiss, expectBody: "file", expectCalled: 1},
+				{expectCache: ResponseCacheMiss, expectBody: "file", expectCalled: 2},
+			},
+			expectContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name: "stream response isn't cached",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().Stream(http.StatusOK, func(w contractshttp.StreamWriter) error {
+					_, err := w.WriteString("stream")
+					return err
+				})
+			},
+			requests: []request{
+				{expectCache: ResponseCacheMiss, expectBody: "stream", expectCalled: 1},
+				{expectCache: ResponseCacheMiss, expectBody: "stream", expectCalled: 2},
+			},
+		},
+		{
+			name: "the sorted query and the vary headers are part of the key",
+			config: ResponseCacheConfig{
+				Vary: []string{"Accept-Language"},
+			},
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called))
+			},
+			requests: []request{
+				{target: "/?a=1&b=2", expectCache: ResponseCacheMiss, expectBody: "1", expectCalled: 1},
+				{target: "/?b=2&a=1", expectCache: ResponseCacheHit, expectBody: "1", expectCalled: 1},
+				{target: "/?a=2", expectCache: ResponseCacheMiss, expectBody: "2", expectCalled: 2},
+				{target: "/?a=2", header: map[string]string{"Accept-Language": "zh"}, expectCache: ResponseCacheMiss, expectBody: "3", expectCalled: 3},
+				{target: "/other?a=2", expectCache: ResponseCacheMiss, expectBody: "4", expectCalled: 4},
+			},
+		},
+		{
+			name: "skip the responses varying on the headers not in the key",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				appendVary(ctx.(*Context).Instance(), "Accept-Encoding")
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called))
+			},
+			requests: []request{
+				{expectCache: ResponseCacheMiss, expectBody: "1", expectCalled: 1},
+				{expectCache: ResponseCacheMiss, expectBody: "2", expectCalled: 2},
+			},
+		},
+		{
+			name:   "the vary headers of the response are in the key",
+			config: ResponseCacheConfig{Vary: []string{"accept-encoding"}},
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				appendVary(ctx.(*Context).Instance(), "Accept-Encoding")
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called))
+			},
+			requests: []request{
+				{header: map[string]string{"Accept-Encoding": "gzip"}, expectCache: ResponseCacheMiss, expectBody: "1", expectCalled: 1},
+				{header: map[string]string{"Accept-Encoding": "gzip"}, expectCache: ResponseCacheHit, expectBody: "1", expectCalled: 1},
+				{expectCache: ResponseCacheMiss, expectBody: "2", expectCalled: 2},
+			},
+		},
+		{
+			name: "cache control of the handler",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				if ctx.Request().Query("private") != "" {
+					ctx.Response().Header("Cache-Control", "private, max-age=60")
+				} else {
+					ctx.Response().Header("Cache-Control", "max-age=60, s-maxage=10")
+				}
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called))
+			},
+			requests: []request{
+				{target: "/?private=1", expectCache: ResponseCacheMiss, expectBody: "1", expectCalled: 1},
+				{target: "/?private=1", expectCache: ResponseCacheMiss, expectBody: "2", expectCalled: 2},
+				{expectCache: ResponseCacheMiss, expectBody: "3", expectCalled: 3},
+				{advance: 9 * time.Second, expectCache: ResponseCacheHit, expectBody: "3", expectCalled: 3},
+				{advance: time.Second, expectCache: ResponseCacheMiss, expectBody: "4", expectCalled: 4},
+			},
+		},
+		{
+			name: "skip the error and cookie responses",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				if ctx.Request().Query("cookie") != "" {
+					ctx.Response().Cookie(contractshttp.Cookie{Name: "goravel", Value: "session"})
+					return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called))
+				}
+				return ctx.Response().String(http.StatusNotFound, fmt.Sprintf("%d", called))
+			},
+			requests: []request{
+				{expectCache: ResponseCacheMiss, expectBody: "1", expectCalled: 1},
+				{expectCache: ResponseCacheMiss, expectBody: "2", expectCalled: 2},
+				{target: "/?cookie=1", expectCache: ResponseCacheMiss, expectBody: "3", expectCalled: 3},
+				{target: "/?cookie=1", expectCache: ResponseCacheMiss, expectBody: "4", expectCalled: 4},
+			},
+		},
+		{
+			name: "skip the requests with credentials unless the response is shared",
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				if ctx.Request().Query("public") != "" {
+					ctx.Response().Header("Cache-Control", "public, max-age=60")
+				}
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called))
+			},
+			requests: []request{
+				{header: map[string]string{"Authorization": "Bearer token"}, expectCache: ResponseCacheMiss, expectBody: "1", expectCalled: 1},
+				{header: map[string]string{"Authorization": "Bearer token"}, expectCache: ResponseCacheMiss, expectBody: "2", expectCalled: 2},
+				{header: map[string]string{"Cookie": "session=1"}, expectCache: ResponseCacheMiss, expectBody: "3", expectCalled: 3},
+				{header: map[string]string{"Cookie": "session=1"}, expectCache: ResponseCacheMiss, expectBody: "4", expectCalled: 4},
+				{target: "/?public=1", header: map[string]string{"Cookie": "session=1"}, expectCache: ResponseCacheMiss, expectBody: "5", expectCalled: 5},
+				{target: "/?public=1", header: map[string]string{"Cookie": "session=2"}, expectCache: ResponseCacheHit, expectBody: "5", expectCalled: 5},
+			},
+		},
+		{
+			name:   "invalidate by tags",
+			config: ResponseCacheConfig{Tags: []string{"posts"}},
+			handler: func(called int32, ctx contractshttp.Context) contractshttp.Response {
+				AddResponseCacheTags(ctx, "post:"+ctx.Request().Query("id"))
+				return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called))
+			},
+			requests: []request{
+				{target: "/?id=1", expectCache: ResponseCacheMiss, expectBody: "1", expectCalled: 1},
+				{target: "/?id=2", expectCache: ResponseCacheMiss, expectBody: "2", expectCalled: 2},
+				{target: "/?id=1", invalidate: []string{"post:2"}, expectCache: ResponseCacheHit, expectBody: "1", expectCalled: 2},
+				{target: "/?id=2", expectCache: ResponseCacheMiss, expectBody: "3", expectCalled: 3},
+				{target: "/?id=1", invalidate: []string{"posts"}, expectCache: ResponseCacheMiss, expectBody: "4", expectCalled: 4},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			test.config.Store = NewMemoryStore()
+
+			var called atomic.Int32
+			app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+				return test.handler(called.Add(1), ctx)
+			}, ResponseCache(test.config))
+
+			for i, r := range test.requests {
+				now = now.Add(r.advance)
+				require.NoError(t, InvalidateResponseCache(context.Background(), test.config.Store, r.invalidate...))
+
+				method, target := r.method, r.target
+				if method == "" {
+					method = "GET"
+				}
+				if target == "" {
+					target = "/"
+				}
+				req := httptest.NewRequest(method, target, nil)
+				for key, value := range r.header {
+					req.Header.Set(key, value)
+				}
+				resp, err := app.Test(req)
+				require.NoError(t, err)
+
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, r.expectCache, resp.Header.Get(HeaderXCache), "request %d", i)
+				assert.Equal(t, r.expectBody, string(body), "request %d", i)
+				assert.Equal(t, r.expectCalled, called.Load(), "request %d", i)
+				if test.expectContentType != "" {
+					assert.Equal(t, test.expectContentType, resp.Header.Get("Content-Type"), "request %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseCacheStaleWhileRevalidate(t *testing.T) {
+	defer func() {
+		responseCacheNow = time.Now
+	}()
+
+	var now atomic.Int64
+	now.Store(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	responseCacheNow = func() time.Time {
+		return time.Unix(0, now.Load())
+	}
+
+	var called atomic.Int32
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called.Add(1)))
+	}, ResponseCache(ResponseCacheConfig{
+		TTL:                  time.Minute,
+		StaleWhileRevalidate: time.Minute,
+		Store:                NewMemoryStore(),
+	}))
+
+	get := func() (string, string, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.Header.Get(HeaderXCache), resp.Header.Get("Age"), string(body)
+	}
+
+	cache, _, body := get()
+	assert.Equal(t, ResponseCacheMiss, cache)
+	assert.Equal(t, "1", body)
+
+	now.Add(int64(90 * time.Second))
+	cache, age, body := get()
+	assert.Equal(t, ResponseCacheStale, cache)
+	assert.Equal(t, "90", age)
+	assert.Equal(t, "1", body)
+
+	// The stale response is refreshed in the background.
+	assert.Eventually(t, func() bool {
+		cache, _, body = get()
+		return cache == ResponseCacheHit && body == "2"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), called.Load())
+
+	now.Add(int64(3 * time.Minute))
+	cache, _, body = get()
+	assert.Equal(t, ResponseCacheMiss, cache)
+	assert.Equal(t, "3", body)
+}
+
+func TestResponseCacheRevalidateSkipsOuterMiddleware(t *testing.T) {
+	defer func() {
+		responseCacheNow = time.Now
+	}()
+
+	var now atomic.Int64
+	now.Store(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	responseCacheNow = func() time.Time {
+		return time.Unix(0, now.Load())
+	}
+
+	outer, inner := &countMiddleware{}, &countMiddleware{}
+	var called atomic.Int32
+	app := newGlobalMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called.Add(1)))
+	}, outer, ResponseCache(ResponseCacheConfig{
+		TTL:                  time.Minute,
+		StaleWhileRevalidate: time.Minute,
+		Store:                NewMemoryStore(),
+	}), inner)
+
+	var requests int32
+	get := func() string {
+		requests++
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		require.NoError(t, err)
+
+		return resp.Header.Get(HeaderXCache)
+	}
+
+	assert.Equal(t, ResponseCacheMiss, get())
+	now.Add(int64(90 * time.Second))
+	assert.Equal(t, ResponseCacheStale, get())
+	assert.Eventually(t, func() bool {
+		return get() == ResponseCacheHit
+	}, time.Second, 10*time.Millisecond)
+
+	// The refresh runs the middleware after ResponseCache only.
+	assert.Equal(t, int32(2), called.Load())
+	assert.Equal(t, int32(2), inner.count.Load())
+	assert.Equal(t, requests, outer.count.Load())
+}
+
+type countMiddleware struct {
+	count atomic.Int32
+}
+
+func (m *countMiddleware) Signature() string { return "test_count" }
+
+func (m *countMiddleware) Handle(ctx contractshttp.Context) {
+	m.count.Add(1)
+	ctx.Request().Next()
+}
+
+func TestResponseCacheExpiredTagState(t *testing.T) {
+	store := NewMemoryStore()
+	var called atomic.Int32
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, fmt.Sprintf("%d", called.Add(1)))
+	}, ResponseCache(ResponseCacheConfig{Tags: []string{"posts"}, Store: store}))
+
+	get := func() (string, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.Header.Get(HeaderXCache), string(body)
+	}
+
+	cache, body := get()
+	assert.Equal(t, ResponseCacheMiss, cache)
+	assert.Equal(t, "1", body)
+
+	require.NoError(t, InvalidateResponseCache(context.Background(), store, "posts"))
+	cache, body = get()
+	assert.Equal(t, ResponseCacheMiss, cache)
+	assert.Equal(t, "2", body)
+
+	// The responses stored before the tag state expires or is evicted are never served again.
+	require.NoError(t, store.Forget(context.Background(), responseCacheTagKey("posts")))
+	cache, body = get()
+	assert.Equal(t, ResponseCacheMiss, cache)
+	assert.Equal(t, "3", body)
+
+	cache, body = get()
+	assert.Equal(t, ResponseCacheHit, cache)
+	assert.Equal(t, "3", body)
+}
+
+func TestResponseCacheStoreError(t *testing.T) {
+	mockLog := mockslog.NewLog(t)
+	mockLog.EXPECT().Error(mock.Anything).Twice()
+	LogFacade = mockLog
+
+	app := newMiddlewareTestApp(nil, ResponseCache(ResponseCacheConfig{Store: &brokenResponseCacheStore{}}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ResponseCacheMiss, resp.Header.Get(HeaderXCache))
+}
+
+type brokenResponseCacheStore struct{}
+
+func (s *brokenResponseCacheStore) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (s *brokenResponseCacheStore) Put(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (s *brokenResponseCacheStore) Add(context.Context, string, []byte, time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (s *brokenResponseCacheStore) Forget(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func (s *brokenResponseCacheStore) Increment(context.Context, string, time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (s *brokenResponseCacheStore) GetInt64(context.Context, string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestResponseCacheHeadersOfOuterMiddleware(t *testing.T) {
+	var id atomic.Int32
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.Response().Header("X-Custom", "handler")
+		appendVary(ctx.(*Context).Instance(), "Accept-Language")
+		return ctx.Response().String(http.StatusOK, "ok")
+	}, RequestID(RequestIDConfig{Generator: func() string {
+		return fmt.Sprintf("request-%d", id.Add(1))
+	}}), &headerMiddleware{headers: map[string]string{"X-Custom": "outer", "Vary": "Origin"}}, ResponseCache(ResponseCacheConfig{
+		Vary:  []string{"Accept-Language"},
+		Store: NewMemoryStore(),
+	}))
+
+	for i, expectCache := range []string{ResponseCacheMiss, ResponseCacheHit} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, expectCache, resp.Header.Get(HeaderXCache))
+		assert.Equal(t, []string{fmt.Sprintf("request-%d", i+1)}, resp.Header.Values(HeaderRequestID))
+		assert.Equal(t, []string{"handler"}, resp.Header.Values("X-Custom"))
+		assert.Equal(t, []string{"Origin, Accept-Language"}, resp.Header.Values("Vary"))
+	}
+}
+
+// headerMiddleware sets the headers before the next handlers, like the middleware registered before the tested one.
+type headerMiddleware struct {
+	headers map[string]string
+}
+
+func (m *headerMiddleware) Signature() string { return "test_header" }
+
+func (m *headerMiddleware) Handle(ctx contractshttp.Context) {
+	for key, value := range m.headers {
+		ctx.Response().Header(key, value)
+	}
+	ctx.Request().Next()
+}
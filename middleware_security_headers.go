@@ -0,0 +1,155 @@
+package fiber
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+// SecurityHeaderOmit disables a header which has a default value.
+const SecurityHeaderOmit = "-"
+
+// CSPNoncePlaceholder is replaced with the per-request nonce in ContentSecurityPolicy,
+// e.g. "script-src 'self' 'nonce-{nonce}'".
+const CSPNoncePlaceholder = "{nonce}"
+
+type cspNonceKeyType struct{}
+
+var cspNonceKey = cspNonceKeyType{}
+
+// SecurityHeadersConfig configures the SecurityHeaders middleware, the zero value uses the defaults.
+// Set a header to SecurityHeaderOmit to omit it.
+type SecurityHeadersConfig struct {
+	// HSTSMaxAge is the max-age of Strict-Transport-Security in seconds, the header is only sent over HTTPS.
+	// Default: 31536000, a negative value omits the header.
+	HSTSMaxAge int
+	// HSTSExcludeSubdomains drops includeSubDomains from Strict-Transport-Security.
+	HSTSExcludeSubdomains bool
+	// HSTSPreload adds preload to Strict-Transport-Security.
+	HSTSPreload bool
+	// ContentTypeNosniff is the X-Content-Type-Options header. Default: nosniff
+	ContentTypeNosniff string
+	// XFrameOptions is the X-Frame-Options header. Default: SAMEORIGIN
+	XFrameOptions string
+	// ReferrerPolicy is the Referrer-Policy header. Default: strict-origin-when-cross-origin
+	ReferrerPolicy string
+	// PermissionsPolicy is the Permissions-Policy header, it's omitted by default.
+	PermissionsPolicy string
+	// CrossOriginOpenerPolicy is the Cross-Origin-Opener-Policy header. Default: same-origin
+	CrossOriginOpenerPolicy string
+	// CrossOriginEmbedderPolicy is the Cross-Origin-Embedder-Policy header, it's omitted by default.
+	CrossOriginEmbedderPolicy string
+	// ContentSecurityPolicy is the Content-Security-Policy header, it's omitted by default.
+	// CSPNoncePlaceholder is replaced with a nonce generated for every request.
+	ContentSecurityPolicy string
+	// CSPReportOnly sends the policy as Content-Security-Policy-Report-Only.
+	CSPReportOnly bool
+}
+
+type securityHeadersMiddleware struct {
+	config SecurityHeadersConfig
+	hsts   string
+}
+
+func (m *securityHeadersMiddleware) Signature() string {
+	return "goravel:security_headers"
+}
+
+func (m *securityHeadersMiddleware) Handle(ctx contractshttp.Context) {
+	c := ctx.(*Context).Instance()
+
+	if m.hsts != "" && c.Scheme() == "https" {
+		c.Set(fiber.HeaderStrictTransportSecurity, m.hsts)
+	}
+	setSecurityHeader(c, fiber.HeaderXContentTypeOptions, m.config.ContentTypeNosniff)
+	setSecurityHeader(c, fiber.HeaderXFrameOptions, m.config.XFrameOptions)
+	setSecurityHeader(c, fiber.HeaderReferrerPolicy, m.config.ReferrerPolicy)
+	setSecurityHeader(c, fiber.HeaderPermissionsPolicy, m.config.PermissionsPolicy)
+	setSecurityHeader(c, "Cross-Origin-Opener-Policy", m.config.CrossOriginOpenerPolicy)
+	setSecurityHeader(c, "Cross-Origin-Embedder-Policy", m.config.CrossOriginEmbedderPolicy)
+
+	if policy := m.config.ContentSecurityPolicy; policy != "" && policy != SecurityHeaderOmit {
+		if strings.Contains(policy, CSPNoncePlaceholder) {
+			policy = strings.ReplaceAll(policy, CSPNoncePlaceholder, cspNonce(ctx))
+		}
+
+		header := fiber.HeaderContentSecurityPolicy
+		if m.config.CSPReportOnly {
+			header = fiber.HeaderContentSecurityPolicyReportOnly
+		}
+		c.Set(header, policy)
+	}
+
+	ctx.Request().Next()
+}
+
+// SecurityHeaders creates middleware to set the security headers. The CSP nonce is shared with the views rendered
+// by View.Make as csp_nonce and can be read via GetCSPNonce. A route can drop the headers via
+// WithoutMiddleware(SecurityHeaders()), or override them with its own SecurityHeaders, which reuses the nonce
+// of the request.
+func SecurityHeaders(config ...SecurityHeadersConfig) contractshttp.Middleware {
+	var cfg SecurityHeadersConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.HSTSMaxAge == 0 {
+		cfg.HSTSMaxAge = 31536000
+	}
+	if cfg.ContentTypeNosniff == "" {
+		cfg.ContentTypeNosniff = "nosniff"
+	}
+	if cfg.XFrameOptions == "" {
+		cfg.XFrameOptions = "SAMEORIGIN"
+	}
+	if cfg.ReferrerPolicy == "" {
+		cfg.ReferrerPolicy = "strict-origin-when-cross-origin"
+	}
+	if cfg.CrossOriginOpenerPolicy == "" {
+		cfg.CrossOriginOpenerPolicy = "same-origin"
+	}
+
+	var hsts string
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if !cfg.HSTSExcludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	return &securityHeadersMiddleware{config: cfg, hsts: hsts}
+}
+
+// GetCSPNonce returns the CSP nonce of the request, it's empty if the policy doesn't contain CSPNoncePlaceholder.
+func GetCSPNonce(ctx contractshttp.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey).(string)
+
+	return nonce
+}
+
+func cspNonce(ctx contractshttp.Context) string {
+	if nonce := GetCSPNonce(ctx); nonce != "" {
+		return nonce
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	nonce := base64.StdEncoding.EncodeToString(b)
+	ctx.WithValue(cspNonceKey, nonce)
+
+	return nonce
+}
+
+func setSecurityHeader(c fiber.Ctx, key, value string) {
+	if value != "" && value != SecurityHeaderOmit {
+		c.Set(key, value)
+	}
+}
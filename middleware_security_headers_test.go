@@ -0,0 +1,136 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mocksview "github.com/goravel/framework/mocks/view"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        SecurityHeadersConfig
+		https         bool
+		expectHeaders map[string]string
+	}{
+		{
+			name: "defaults",
+			expectHeaders: map[string]string{
+				"Strict-Transport-Security":    "",
+				"X-Content-Type-Options":       "nosniff",
+				"X-Frame-Options":              "SAMEORIGIN",
+				"Referrer-Policy":              "strict-origin-when-cross-origin",
+				"Permissions-Policy":           "",
+				"Cross-Origin-Opener-Policy":   "same-origin",
+				"Cross-Origin-Embedder-Policy": "",
+				"Content-Security-Policy":      "",
+			},
+		},
+		{
+			name:  "hsts over https",
+			https: true,
+			expectHeaders: map[string]string{
+				"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+			},
+		},
+		{
+			name:   "custom hsts",
+			config: SecurityHeadersConfig{HSTSMaxAge: 60, HSTSExcludeSubdomains: true, HSTSPreload: true},
+			https:  true,
+			expectHeaders: map[string]string{
+				"Strict-Transport-Security": "max-age=60; preload",
+			},
+		},
+		{
+			name:   "disabled hsts",
+			config: SecurityHeadersConfig{HSTSMaxAge: -1},
+			https:  true,
+			expectHeaders: map[string]string{
+				"Strict-Transport-Security": "",
+			},
+		},
+		{
+			name: "custom headers",
+			config: SecurityHeadersConfig{
+				XFrameOptions:             SecurityHeaderOmit,
+				PermissionsPolicy:         "camera=()",
+				CrossOriginEmbedderPolicy: "require-corp",
+				ContentSecurityPolicy:     "default-src 'self'",
+			},
+			expectHeaders: map[string]string{
+				"X-Frame-Options":              "",
+				"Permissions-Policy":           "camera=()",
+				"Cross-Origin-Embedder-Policy": "require-corp",
+				"Content-Security-Policy":      "default-src 'self'",
+			},
+		},
+		{
+			name:   "report only",
+			config: SecurityHeadersConfig{ContentSecurityPolicy: "default-src 'self'", CSPReportOnly: true},
+			expectHeaders: map[string]string{
+				"Content-Security-Policy":             "",
+				"Content-Security-Policy-Report-Only": "default-src 'self'",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newTrustedMiddlewareTestApp(true, nil, SecurityHeaders(test.config))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if test.https {
+				req.Header.Set("X-Forwarded-Proto", "https")
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			for key, value := range test.expectHeaders {
+				assert.Equal(t, value, resp.Header.Get(key), key)
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersNonce(t *testing.T) {
+	defer func() {
+		ViewFacade = nil
+	}()
+
+	mockView := mocksview.NewView(t)
+	mockView.EXPECT().GetShared().Return(nil).Once()
+	ViewFacade = mockView
+
+	var (
+		nonce string
+		view  *HtmlResponse
+	)
+	app := newMiddlewareTestApp(func(ctx contractshttp.Context) contractshttp.Response {
+		nonce = GetCSPNonce(ctx)
+		view = ctx.Response().View().Make("welcome.tmpl").(*HtmlResponse)
+		return ctx.Response().String(http.StatusOK, "ok")
+	},
+		SecurityHeaders(SecurityHeadersConfig{ContentSecurityPolicy: "script-src 'self' 'nonce-{nonce}'"}),
+		// A route level override reuses the nonce of the request.
+		SecurityHeaders(SecurityHeadersConfig{ContentSecurityPolicy: "script-src 'nonce-{nonce}'"}),
+	)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9+/]{22}==$`), nonce)
+	assert.Equal(t, "script-src 'nonce-"+nonce+"'", resp.Header.Get("Content-Security-Policy"))
+	assert.Equal(t, map[string]any{"csp_nonce": nonce}, view.data)
+
+	resp, err = newMiddlewareTestApp(nil, SecurityHeaders(SecurityHeadersConfig{ContentSecurityPolicy: "script-src 'nonce-{nonce}'"})).
+		Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.NotEqual(t, "script-src 'nonce-"+nonce+"'", resp.Header.Get("Content-Security-Policy"))
+}
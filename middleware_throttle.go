@@ -0,0 +1,151 @@
+package fiber
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+const (
+	// ThrottleFixedWindow counts the hits in consecutive windows, a burst of up to 2x Max is possible
+	// around the boundary of two windows.
+	ThrottleFixedWindow = "fixed_window"
+	// ThrottleSlidingWindow weights the hits of the previous window by its overlap with the sliding window,
+	// which smooths out the bursts of the fixed window at the cost of one more store read.
+	ThrottleSlidingWindow = "sliding_window"
+
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// Limiter defines how the Throttle middleware limits requests.
+type Limiter struct {
+	// Max is the number of requests allowed in a window.
+	Max int
+	// Window is the length of the window.
+	Window time.Duration
+	// Algorithm is ThrottleFixedWindow (default) or ThrottleSlidingWindow.
+	Algorithm string
+	// Key returns the identity of the requester, e.g. the user ID, the IP or the route.
+	// Default: the client IP.
+	Key func(ctx contractshttp.Context) string
+	// Store keeps the counters, use NewCacheStore for multi-instance deployments.
+	// Default: an in-memory store for this process only.
+	Store Store
+	// Response renders the response when the limit is exceeded.
+	// Default: abort with 429 Too Many Requests.
+	Response func(ctx contractshttp.Context)
+}
+
+var (
+	limiters     = make(map[string]Limiter)
+	limitersLock sync.RWMutex
+
+	defaultThrottleStore = NewMemoryStore()
+
+	// throttleNow is replaced in tests to control the windows.
+	throttleNow = time.Now
+)
+
+// RegisterLimiter registers a named limiter for the Throttle middleware, it panics if the limiter is invalid.
+// RegisterLimiter 注册 Throttle 中间件使用的限流器
+func RegisterLimiter(name string, limiter Limiter) {
+	if limiter.Max <= 0 {
+		panic(fmt.Errorf("the max of limiter %s must be greater than 0", name))
+	}
+	if limiter.Window < time.Second {
+		panic(fmt.Errorf("the window of limiter %s must be at least 1 second", name))
+	}
+	if limiter.Algorithm == "" {
+		limiter.Algorithm = ThrottleFixedWindow
+	}
+	if limiter.Algorithm != ThrottleFixedWindow && limiter.Algorithm != ThrottleSlidingWindow {
+		panic(fmt.Errorf("unsupported throttle algorithm: %s", limiter.Algorithm))
+	}
+	if limiter.Key == nil {
+		limiter.Key = func(ctx contractshttp.Context) string {
+			return ctx.Request().Ip()
+		}
+	}
+	if limiter.Store == nil {
+		limiter.Store = defaultThrottleStore
+	}
+
+	limitersLock.Lock()
+	defer limitersLock.Unlock()
+
+	limiters[name] = limiter
+}
+
+type throttleMiddleware struct {
+	name string
+}
+
+func (m *throttleMiddleware) Signature() string {
+	return "goravel:throttle:" + m.name
+}
+
+func (m *throttleMiddleware) Handle(ctx contractshttp.Context) {
+	limitersLock.RLock()
+	limiter, ok := limiters[m.name]
+	limitersLock.RUnlock()
+	if !ok {
+		panic(fmt.Errorf("limiter %s is not registered", m.name))
+	}
+
+	now := throttleNow()
+	windowStart := now.Truncate(limiter.Window)
+	reset := windowStart.Add(limiter.Window).Sub(now)
+	key := fmt.Sprintf("throttle:%s:%s", m.name, limiter.Key(ctx))
+
+	hits, err := limiter.Store.Increment(ctx, fmt.Sprintf("%s:%d", key, windowStart.Unix()), 2*limiter.Window)
+	if err != nil {
+		// Fail open, an unavailable store shouldn't take the whole application down.
+		LogFacade.Error(fmt.Errorf("throttle failed to increment %s: %w", key, err))
+		ctx.Request().Next()
+		return
+	}
+
+	if limiter.Algorithm == ThrottleSlidingWindow {
+		previous, err := limiter.Store.GetInt64(ctx, fmt.Sprintf("%s:%d", key, windowStart.Add(-limiter.Window).Unix()))
+		if err != nil {
+			LogFacade.Error(fmt.Errorf("throttle failed to get %s: %w", key, err))
+			ctx.Request().Next()
+			return
+		}
+
+		weight := float64(reset) / float64(limiter.Window)
+		hits += int64(math.Floor(float64(previous) * weight))
+	}
+
+	remaining := max(int64(limiter.Max)-hits, 0)
+	resetSeconds := strconv.Itoa(int(math.Ceil(reset.Seconds())))
+
+	ctx.Response().Header(HeaderRateLimitLimit, strconv.Itoa(limiter.Max))
+	ctx.Response().Header(HeaderRateLimitRemaining, strconv.FormatInt(remaining, 10))
+	ctx.Response().Header(HeaderRateLimitReset, resetSeconds)
+
+	if hits > int64(limiter.Max) {
+		ctx.Response().Header(HeaderRetryAfter, resetSeconds)
+		if limiter.Response != nil {
+			limiter.Response(ctx)
+		} else {
+			ctx.Request().Abort(contractshttp.StatusTooManyRequests)
+		}
+		return
+	}
+
+	ctx.Request().Next()
+}
+
+// Throttle creates middleware to limit requests with the named limiter, see RegisterLimiter.
+// The limiter name is a part of the signature, so a single limiter can be excluded via WithoutMiddleware.
+func Throttle(name string) contractshttp.Middleware {
+	return &throttleMiddleware{name: name}
+}
@@ -0,0 +1,172 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottle(t *testing.T) {
+	defer func() {
+		throttleNow = time.Now
+	}()
+
+	// 15 seconds into a one minute window
+	throttleNow = func() time.Time {
+		return time.Date(2025, 1, 1, 0, 0, 15, 0, time.UTC)
+	}
+	previousWindow := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name    string
+		limiter Limiter
+		setup   func(store Store)
+		// the X-User header of each request
+		requests      []string
+		expectStatus  []int
+		expectHeaders map[string]string
+	}{
+		{
+			name:         "fixed window",
+			limiter:      Limiter{Max: 2, Window: time.Minute},
+			requests:     []string{"", "", ""},
+			expectStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			expectHeaders: map[string]string{
+				HeaderRateLimitLimit:     "2",
+				HeaderRateLimitRemaining: "0",
+				HeaderRateLimitReset:     "45",
+				HeaderRetryAfter:         "45",
+			},
+		},
+		{
+			name: "sliding window counts the overlap of the previous window",
+			limiter: Limiter{Max: 4, Window: time.Minute, Algorithm: ThrottleSlidingWindow, Key: func(ctx contractshttp.Context) string {
+				return "sliding"
+			}},
+			setup: func(store Store) {
+				for i := 0; i < 4; i++ {
+					_, err := store.Increment(context.Background(), fmt.Sprintf("throttle:test:sliding:%d", previousWindow), time.Hour)
+					require.NoError(t, err)
+				}
+			},
+			requests:     []string{"", ""},
+			expectStatus: []int{http.StatusOK, http.StatusTooManyRequests},
+			expectHeaders: map[string]string{
+				HeaderRateLimitLimit:     "4",
+				HeaderRateLimitRemaining: "0",
+				HeaderRetryAfter:         "45",
+			},
+		},
+		{
+			name: "limit by key",
+			limiter: Limiter{Max: 1, Window: time.Minute, Key: func(ctx contractshttp.Context) string {
+				return ctx.Request().Header("X-User")
+			}},
+			requests:     []string{"1", "2", "1"},
+			expectStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "custom response",
+			limiter: Limiter{Max: 1, Window: time.Minute, Response: func(ctx contractshttp.Context) {
+				ctx.Response().String(http.StatusServiceUnavailable, "slow down").Abort()
+			}},
+			requests:     []string{"", ""},
+			expectStatus: []int{http.StatusOK, http.StatusServiceUnavailable},
+			expectHeaders: map[string]string{
+				HeaderRetryAfter: "45",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			test.limiter.Store = store
+			RegisterLimiter("test", test.limiter)
+			if test.setup != nil {
+				test.setup(store)
+			}
+
+			app := newMiddlewareTestApp(nil, Throttle("test"))
+
+			var resp *http.Response
+			for i, user := range test.requests {
+				req := httptest.NewRequest("GET", "/", nil)
+				req.Header.Set("X-User", user)
+
+				var err error
+				resp, err = app.Test(req)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectStatus[i], resp.StatusCode, "request %d", i)
+			}
+
+			for key, value := range test.expectHeaders {
+				assert.Equal(t, value, resp.Header.Get(key), key)
+			}
+		})
+	}
+}
+
+func TestThrottleStoreError(t *testing.T) {
+	mockLog := mockslog.NewLog(t)
+	mockLog.EXPECT().Error(mock.Anything).Twice()
+	LogFacade = mockLog
+
+	RegisterLimiter("broken", Limiter{Max: 1, Window: time.Minute, Store: &brokenThrottleStore{}})
+	app := newMiddlewareTestApp(nil, Throttle("broken"))
+
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "request %d", i)
+	}
+}
+
+func TestRegisterLimiter(t *testing.T) {
+	assert.PanicsWithError(t, "the max of limiter invalid must be greater than 0", func() {
+		RegisterLimiter("invalid", Limiter{Window: time.Minute})
+	})
+	assert.PanicsWithError(t, "the window of limiter invalid must be at least 1 second", func() {
+		RegisterLimiter("invalid", Limiter{Max: 1})
+	})
+	assert.PanicsWithError(t, "unsupported throttle algorithm: token_bucket", func() {
+		RegisterLimiter("invalid", Limiter{Max: 1, Window: time.Minute, Algorithm: "token_bucket"})
+	})
+	assert.Equal(t, "goravel:throttle:api", Throttle("api").Signature())
+}
+
+type brokenThrottleStore struct{}
+
+func (s *brokenThrottleStore) Increment(context.Context, string, time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (s *brokenThrottleStore) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (s *brokenThrottleStore) Put(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (s *brokenThrottleStore) Add(context.Context, string, []byte, time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (s *brokenThrottleStore) Forget(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func (s *brokenThrottleStore) GetInt64(context.Context, string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
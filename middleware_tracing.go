@@ -0,0 +1,142 @@
+package fiber
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracingInstrumentationName = "github.com/goravel/fiber"
+
+// TracingConfig configures the Tracing middleware, the zero value uses the global tracer provider of otel.
+type TracingConfig struct {
+	// TracerProvider creates the spans, e.g. the one of the telemetry facade.
+	// Default: a provider exporting to Exporter, or the global provider if Exporter is nil.
+	TracerProvider trace.TracerProvider
+	// Exporter receives the spans synchronously when TracerProvider is nil, e.g. NewStdoutSpanExporter() in
+	// development or NewMemorySpanExporter() in tests, so no collector is needed.
+	Exporter sdktrace.SpanExporter
+	// Propagator extracts the parent span from the request headers.
+	// Default: W3C traceparent/tracestate and baggage.
+	Propagator propagation.TextMapPropagator
+	// Except are the paths not traced, matched via path.Match without the leading and trailing slashes,
+	// e.g. "health" or "metrics/*".
+	Except []string
+}
+
+type tracingMiddleware struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	except     []string
+}
+
+func (m *tracingMiddleware) Signature() string {
+	return "goravel:tracing"
+}
+
+func (m *tracingMiddleware) Handle(ctx contractshttp.Context) {
+	request := ctx.Request()
+	if matchPathPatterns(m.except, request.Path()) {
+		request.Next()
+		return
+	}
+
+	c := ctx.(*Context).Instance()
+	method := request.Method()
+	attributes := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.URLPath(request.Path()),
+			semconv.URLScheme(c.Scheme()),
+			semconv.ServerAddress(c.Hostname()),
+			semconv.ClientAddress(request.Ip()),
+			semconv.NetworkPeerAddress(c.RequestCtx().RemoteIP().String()),
+			semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+		),
+	}
+
+	// The route isn't known before the handlers ran, so the span is renamed after them.
+	parent := m.propagator.Extract(ctx.Context(), propagation.HeaderCarrier(request.Headers()))
+	spanCtx, span := m.tracer.Start(parent, method, attributes...)
+	defer span.End()
+
+	// The span context is shared with the later handlers, so the database and HTTP client calls join the trace.
+	ctx.WithContext(spanCtx)
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			span.RecordError(fmt.Errorf("panic: %v", recovered))
+			span.SetStatus(codes.Error, "panic")
+			span.SetAttributes(semconv.HTTPResponseStatusCode(contractshttp.StatusInternalServerError))
+			panic(recovered)
+		}
+	}()
+
+	request.Next()
+
+	if invalidFiber(c) {
+		return
+	}
+
+	status := c.Response().StatusCode()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if route := matchedRoute(c); route != "" {
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	}
+	if status >= contractshttp.StatusInternalServerError {
+		span.SetStatus(codes.Error, "")
+	}
+}
+
+// Tracing creates middleware to record a server span for every request, the parent span is extracted from the
+// traceparent and tracestate headers and the span is named by the route template.
+func Tracing(config ...TracingConfig) contractshttp.Middleware {
+	var cfg TracingConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.TracerProvider == nil {
+		if cfg.Exporter != nil {
+			cfg.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(cfg.Exporter))
+		} else {
+			cfg.TracerProvider = otel.GetTracerProvider()
+		}
+	}
+	if cfg.Propagator == nil {
+		cfg.Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+
+	return &tracingMiddleware{
+		tracer:     cfg.TracerProvider.Tracer(tracingInstrumentationName),
+		propagator: cfg.Propagator,
+		except:     trimPathPatterns(cfg.Except),
+	}
+}
+
+// NewMemorySpanExporter creates an exporter keeping the spans in memory, it's used to assert the spans in tests.
+func NewMemorySpanExporter() *tracetest.InMemoryExporter {
+	return tracetest.NewInMemoryExporter()
+}
+
+// NewStdoutSpanExporter creates an exporter printing the spans as JSON, it writes to stdout if the writer is empty.
+func NewStdoutSpanExporter(writer ...io.Writer) (sdktrace.SpanExporter, error) {
+	var w io.Writer = os.Stdout
+	if len(writer) > 0 {
+		w = writer[0]
+	}
+
+	return stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+}
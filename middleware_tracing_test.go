@@ -0,0 +1,166 @@
+package fiber
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		switch ctx.Request().Path() {
+		case "/missing":
+			return ctx.Response().String(http.StatusNotFound, "not found")
+		case "/error":
+			return ctx.Response().String(http.StatusInternalServerError, "error")
+		default:
+			// The handler sees the span via the context, so the downstream calls join the trace.
+			return ctx.Response().String(http.StatusOK, trace.SpanContextFromContext(ctx.Context()).TraceID().String())
+		}
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		traceparent  string
+		expectSpan   bool
+		expectName   string
+		expectStatus codes.Code
+		expectAttrs  map[attribute.Key]attribute.Value
+		assert       func(t *testing.T, span tracetest.SpanStub, body string)
+	}{
+		{
+			name:        "join the incoming trace",
+			path:        "/users/1",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectSpan:  true,
+			expectName:  "GET /*",
+			expectAttrs: map[attribute.Key]attribute.Value{
+				"http.request.method":       attribute.StringValue("GET"),
+				"http.route":                attribute.StringValue("/*"),
+				"http.response.status_code": attribute.IntValue(http.StatusOK),
+				"url.path":                  attribute.StringValue("/users/1"),
+				"client.address":            attribute.StringValue("0.0.0.0"),
+				"network.peer.address":      attribute.StringValue("0.0.0.0"),
+			},
+			assert: func(t *testing.T, span tracetest.SpanStub, body string) {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+				assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+				assert.True(t, span.Parent.IsRemote())
+				assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", body)
+			},
+		},
+		{
+			name:       "start a new trace",
+			path:       "/users",
+			expectSpan: true,
+			expectName: "GET /*",
+			assert: func(t *testing.T, span tracetest.SpanStub, body string) {
+				assert.False(t, span.Parent.IsValid())
+				assert.Equal(t, span.SpanContext.TraceID().String(), body)
+			},
+		},
+		{
+			name:         "server error",
+			path:         "/error",
+			expectSpan:   true,
+			expectName:   "GET /*",
+			expectStatus: codes.Error,
+		},
+		{
+			name:        "not found by the route",
+			path:        "/missing",
+			expectSpan:  true,
+			expectName:  "GET /*",
+			expectAttrs: map[attribute.Key]attribute.Value{"http.route": attribute.StringValue("/*")},
+		},
+		{
+			name: "excluded path",
+			path: "/health",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := NewMemorySpanExporter()
+			app := newMiddlewareTestApp(handler, Tracing(TracingConfig{Exporter: exporter, Except: []string{"/health"}}))
+
+			req := httptest.NewRequest("GET", test.path, nil)
+			if test.traceparent != "" {
+				req.Header.Set("traceparent", test.traceparent)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			spans := exporter.GetSpans()
+			if !test.expectSpan {
+				assert.Empty(t, spans)
+				return
+			}
+
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, test.expectName, span.Name)
+			assert.Equal(t, test.expectStatus, span.Status.Code)
+
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, attr := range span.Attributes {
+				attrs[attr.Key] = attr.Value
+			}
+			for key, value := range test.expectAttrs {
+				assert.Equal(t, value, attrs[key], key)
+			}
+			if test.assert != nil {
+				test.assert(t, span, string(body))
+			}
+		})
+	}
+}
+
+func TestTracingUnmatchedRoute(t *testing.T) {
+	routes = make(map[string]map[string]contractshttp.Info)
+	NewAction(contractshttp.MethodGet, "/", "home")
+	defer func() {
+		routes = make(map[string]map[string]contractshttp.Info)
+	}()
+
+	exporter := NewMemorySpanExporter()
+	app := newGlobalMiddlewareTestApp(nil, Tracing(TracingConfig{Exporter: exporter}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/missing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET", spans[0].Name)
+	for _, attr := range spans[0].Attributes {
+		assert.NotEqual(t, attribute.Key("http.route"), attr.Key)
+	}
+}
+
+func TestNewStdoutSpanExporter(t *testing.T) {
+	var buffer bytes.Buffer
+	exporter, err := NewStdoutSpanExporter(&buffer)
+	require.NoError(t, err)
+
+	app := newMiddlewareTestApp(nil, Tracing(TracingConfig{Exporter: exporter}))
+	_, err = app.Test(httptest.NewRequest("GET", "/users", nil))
+	require.NoError(t, err)
+
+	assert.Contains(t, buffer.String(), `"Name": "GET /*"`)
+	assert.Equal(t, "goravel:tracing", Tracing().Signature())
+}
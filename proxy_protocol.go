@@ -0,0 +1,218 @@
+package fiber
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyProtocolHeaderTimeout bounds how long a connection may take to send its PROXY protocol header.
+var proxyProtocolHeaderTimeout = 5 * time.Second
+
+var (
+	proxyProtocolV1Prefix    = []byte("PROXY ")
+	proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// proxyProtocolListener parses the PROXY protocol v1/v2 header sent by load balancers (AWS NLB, HAProxy)
+// so the connection reports the original client address as its remote address.
+// Connections from upstreams outside the trusted networks are served as-is, an empty list trusts no upstream
+// because the header is optional, a trusted client could spoof its address otherwise.
+type proxyProtocolListener struct {
+	net.Listener
+	trusted []*net.IPNet
+}
+
+func newProxyProtocolListener(l net.Listener, trusted []*net.IPNet) net.Listener {
+	return &proxyProtocolListener{Listener: l, trusted: trusted}
+}
+
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.isTrusted(conn.RemoteAddr()) {
+		return conn, nil
+	}
+
+	return &proxyProtocolConn{Conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+func (l *proxyProtocolListener) isTrusted(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, network := range l.trusted {
+		if network.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// proxyProtocolConn reads the header lazily on the first Read or RemoteAddr call,
+// so a slow upstream never blocks the accept loop.
+type proxyProtocolConn struct {
+	net.Conn
+	reader     *bufio.Reader
+	once       sync.Once
+	err        error
+	remoteAddr net.Addr
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.reader.Read(b)
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyProtocolConn) readHeader() {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(proxyProtocolHeaderTimeout)); err != nil {
+		c.err = err
+		return
+	}
+	defer func() {
+		if err := c.Conn.SetReadDeadline(time.Time{}); err != nil && c.err == nil {
+			c.err = err
+		}
+	}()
+
+	first, err := c.reader.Peek(1)
+	if err != nil {
+		c.err = err
+		return
+	}
+
+	switch first[0] {
+	case proxyProtocolV1Prefix[0]:
+		if prefix, err := c.reader.Peek(len(proxyProtocolV1Prefix)); err == nil && bytes.Equal(prefix, proxyProtocolV1Prefix) {
+			c.remoteAddr, c.err = readProxyProtocolV1(c.reader)
+		}
+	case proxyProtocolV2Signature[0]:
+		if signature, err := c.reader.Peek(len(proxyProtocolV2Signature)); err == nil && bytes.Equal(signature, proxyProtocolV2Signature) {
+			c.remoteAddr, c.err = readProxyProtocolV2(c.reader)
+		}
+	}
+}
+
+// readProxyProtocolV1 parses the human-readable header: PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n
+func readProxyProtocolV1(reader *bufio.Reader) (net.Addr, error) {
+	// The longest v1 header is 107 bytes, see https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt
+	var line []byte
+	for len(line) < 107 {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, errors.New("invalid proxy protocol v1 header")
+	}
+
+	fields := strings.Fields(string(line[:len(line)-2]))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, errors.New("invalid proxy protocol v1 header")
+	}
+
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.Atoi(fields[4])
+	if ip == nil || err != nil || port < 0 || port > 65535 {
+		return nil, errors.New("invalid proxy protocol v1 source address")
+	}
+
+	return &net.TCPAddr{IP: ip, Port: port}, nil
+}
+
+// readProxyProtocolV2 parses the binary header, TLVs are skipped.
+func readProxyProtocolV2(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+
+	version, command := header[12]>>4, header[12]&0x0F
+	if version != 2 {
+		return nil, fmt.Errorf("unsupported proxy protocol version: %d", version)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+
+	// LOCAL connections are health checks from the proxy itself and keep the real address.
+	if command == 0 {
+		return nil, nil
+	}
+
+	switch header[13] >> 4 {
+	case 1:
+		if len(payload) < 12 {
+			return nil, errors.New("invalid proxy protocol v2 IPv4 address")
+		}
+		return &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}, nil
+	case 2:
+		if len(payload) < 36 {
+			return nil, errors.New("invalid proxy protocol v2 IPv6 address")
+		}
+		return &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// parseTrustedNetworks accepts both CIDRs and single IP addresses.
+func parseTrustedNetworks(items []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, item := range items {
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address: %s", item)
+			}
+			if ip.To4() != nil {
+				item += "/32"
+			} else {
+				item += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR: %s", item)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
@@ -0,0 +1,261 @@
+package fiber
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	mocksconfig "github.com/goravel/framework/mocks/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyProtocolListener(t *testing.T) {
+	v2Header := func(command, family byte, addresses []byte) []byte {
+		header := append([]byte{}, proxyProtocolV2Signature...)
+		header = append(header, 0x20|command, family<<4|0x01, 0, 0)
+		binary.BigEndian.PutUint16(header[14:16], uint16(len(addresses)))
+
+		return append(header, addresses...)
+	}
+	ipv4Addresses := append(append([]byte{203, 0, 113, 7, 10, 0, 0, 1}, 0xDC, 0x04), 0x01, 0xBB)
+	ipv6Addresses := append(append(net.ParseIP("2001:db8::7").To16(), net.ParseIP("2001:db8::1").To16()...), 0xDC, 0x04, 0x01, 0xBB)
+
+	tests := []struct {
+		name       string
+		trusted    []string
+		header     []byte
+		expectIP   string
+		expectBody string
+	}{
+		{
+			name:     "v1 tcp4",
+			header:   []byte("PROXY TCP4 203.0.113.7 10.0.0.1 56324 443\r\n"),
+			expectIP: "203.0.113.7",
+		},
+		{
+			name:     "v1 tcp6",
+			header:   []byte("PROXY TCP6 2001:db8::7 2001:db8::1 56324 443\r\n"),
+			expectIP: "2001:db8::7",
+		},
+		{
+			name:     "v1 unknown",
+			header:   []byte("PROXY UNKNOWN\r\n"),
+			expectIP: "127.0.0.1",
+		},
+		{
+			name:     "v2 ipv4",
+			header:   v2Header(0x01, 0x01, ipv4Addresses),
+			expectIP: "203.0.113.7",
+		},
+		{
+			name:     "v2 ipv6",
+			header:   v2Header(0x01, 0x02, ipv6Addresses),
+			expectIP: "2001:db8::7",
+		},
+		{
+			name:     "v2 local",
+			header:   v2Header(0x00, 0x01, ipv4Addresses),
+			expectIP: "127.0.0.1",
+		},
+		{
+			name:     "without header",
+			expectIP: "127.0.0.1",
+		},
+		{
+			name:       "no trusted upstream",
+			trusted:    []string{},
+			header:     []byte("PROXY TCP4 203.0.113.7 10.0.0.1 56324 443\r\n"),
+			expectIP:   "127.0.0.1",
+			expectBody: "PROXY TCP4 203.0.113.7 10.0.0.1 56324 443\r\n",
+		},
+		{
+			name:       "untrusted upstream",
+			trusted:    []string{"10.0.0.0/8"},
+			header:     []byte("PROXY TCP4 203.0.113.7 10.0.0.1 56324 443\r\n"),
+			expectIP:   "127.0.0.1",
+			expectBody: "PROXY TCP4 203.0.113.7 10.0.0.1 56324 443\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The test connections come from the trusted loopback unless the case sets the upstreams.
+			upstreams := test.trusted
+			if upstreams == nil {
+				upstreams = []string{"127.0.0.0/8"}
+			}
+			trusted, err := parseTrustedNetworks(upstreams)
+			require.NoError(t, err)
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer func() {
+				_ = l.Close()
+			}()
+			l = newProxyProtocolListener(l, trusted)
+
+			go func() {
+				conn, err := net.Dial("tcp", l.Addr().String())
+				if err != nil {
+					return
+				}
+				defer func() {
+					_ = conn.Close()
+				}()
+				_, _ = conn.Write(append(test.header, "hello"...))
+			}()
+
+			conn, err := l.Accept()
+			require.NoError(t, err)
+			defer func() {
+				_ = conn.Close()
+			}()
+
+			assert.Equal(t, test.expectIP, conn.RemoteAddr().(*net.TCPAddr).IP.String())
+
+			body, err := io.ReadAll(conn)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectBody+"hello", string(body))
+		})
+	}
+}
+
+func TestProxyProtocolInvalidHeader(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = l.Close()
+	}()
+	l = newProxyProtocolListener(l, []*net.IPNet{{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}})
+
+	go func() {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		_, _ = conn.Write([]byte("PROXY TCP4 invalid\r\nhello"))
+	}()
+
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err = conn.Read(make([]byte, 5))
+	assert.EqualError(t, err, "invalid proxy protocol v1 header")
+}
+
+func TestParseTrustedNetworks(t *testing.T) {
+	networks, err := parseTrustedNetworks([]string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32", "::1"})
+	require.NoError(t, err)
+	require.Len(t, networks, 4)
+	assert.Equal(t, "10.0.0.0/8", networks[0].String())
+	assert.Equal(t, "192.168.1.1/32", networks[1].String())
+	assert.Equal(t, "2001:db8::/32", networks[2].String())
+	assert.Equal(t, "::1/128", networks[3].String())
+
+	_, err = parseTrustedNetworks([]string{"10.0.0.0/33"})
+	assert.EqualError(t, err, "invalid CIDR: 10.0.0.0/33")
+
+	_, err = parseTrustedNetworks([]string{"localhost"})
+	assert.EqualError(t, err, "invalid IP address: localhost")
+}
+
+func TestProxyProtocolRoute(t *testing.T) {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
+	mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+	mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(true).Once()
+	mockConfig.EXPECT().Get("http.drivers.fiber.proxy_protocol_trusted").Return([]string{"127.0.0.1"}).Once()
+	mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
+	mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
+
+	route := &Route{
+		config: mockConfig,
+		driver: "fiber",
+	}
+	require.NoError(t, route.init(nil))
+
+	route.Get("/ip", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, ctx.Request().Ip())
+	})
+
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+
+	go func() {
+		assert.NoError(t, route.Run("127.0.0.1:3042"))
+	}()
+	defer func() {
+		assert.NoError(t, route.Shutdown())
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	conn, err := net.Dial("tcp", "127.0.0.1:3042")
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err = conn.Write([]byte("PROXY TCP4 203.0.113.7 127.0.0.1 56324 3042\r\nGET /ip HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "203.0.113.7", string(body))
+}
+
+func TestProxyProtocolRouteWithoutTrustedUpstreams(t *testing.T) {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(true).Once()
+	mockConfig.EXPECT().Get("http.drivers.fiber.proxy_protocol_trusted").Return([]string{}).Once()
+
+	route := &Route{
+		config: mockConfig,
+		driver: "fiber",
+	}
+	assert.EqualError(t, route.init(nil), "http.drivers.fiber.proxy_protocol_trusted is required when the proxy protocol is enabled")
+}
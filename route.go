@@ -3,11 +3,16 @@ package fiber
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -16,12 +21,12 @@ import (
 	"github.com/goravel/framework/contracts/config"
 	contractshttp "github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/contracts/route"
-	frameworkmiddleware "github.com/goravel/framework/http/middleware"
 	"github.com/goravel/framework/support"
 	"github.com/goravel/framework/support/color"
 	"github.com/goravel/framework/support/json"
 	"github.com/goravel/framework/support/str"
 	"github.com/spf13/cast"
+	"github.com/valyala/fasthttp"
 )
 
 // map[path]map[method]info
@@ -33,12 +38,19 @@ var globalRecoverCallback func(ctx contractshttp.Context, err any) = defaultReco
 // Route fiber 路由
 type Route struct {
 	route.Router
-	config           config.Config
-	driver           string
-	fallback         contractshttp.HandlerFunc
-	globalMiddleware []contractshttp.Middleware
-	instance         *fiber.App
-	listenConfig     fiber.ListenConfig
+	config               config.Config
+	driver               string
+	fallback             contractshttp.HandlerFunc
+	fallbackRegistered   bool
+	globalMiddleware     []contractshttp.Middleware
+	globalHandlersOffset int
+	hooks                *Hooks
+	instance             *fiber.App
+	listenConfig         fiber.ListenConfig
+	proxyProtocolTrusted []*net.IPNet
+	redirectServer       *fasthttp.Server
+	started              atomic.Bool
+	useProxyProtocol     bool
 }
 
 // NewRoute creates new fiber route instance
@@ -53,7 +65,7 @@ func NewRoute(config config.Config, parameters map[string]any) (*Route, error) {
 	globalMiddleware := []contractshttp.Middleware{
 		Timeout(timeout),
 		Cors(),
-		frameworkmiddleware.CheckForMaintenanceMode(),
+		CheckForMaintenanceMode(MaintenanceConfig{ConfigKey: fmt.Sprintf("http.drivers.%s.maintenance", driver)}),
 	}
 
 	route := &Route{
@@ -101,11 +113,10 @@ func (r *Route) GetRoutes() []contractshttp.Info {
 	return infos
 }
 
-// GlobalMiddleware set global middleware
+// GlobalMiddleware appends global middleware, it panics if the server is started
 // GlobalMiddleware 设置全局中间件
 func (r *Route) GlobalMiddleware(middleware ...contractshttp.Middleware) {
-	r.globalMiddleware = append(r.globalMiddleware, middleware...)
-	if err := r.init(r.globalMiddleware); err != nil {
+	if err := r.replaceGlobalMiddleware(append(r.globalMiddleware, middleware...)); err != nil {
 		panic(err)
 	}
 }
@@ -113,6 +124,12 @@ func (r *Route) GlobalMiddleware(middleware ...contractshttp.Middleware) {
 // Listen listen server
 // Listen 监听服务器
 func (r *Route) Listen(l net.Listener) error {
+	l = r.wrapListener(l)
+
+	if err := r.hooks.executeOnRoute(); err != nil {
+		return err
+	}
+
 	r.registerFallback()
 	r.outputRoutes()
 	color.Green().Println("[HTTP] Listening on: " + str.Of(l.Addr().String()).Start("http://").String())
@@ -131,18 +148,15 @@ func (r *Route) ListenTLS(l net.Listener) error {
 // ListenTLSWithCert listen TLS server with cert file and key file
 // ListenTLSWithCert 使用证书文件和密钥文件监听 TLS 服务器
 func (r *Route) ListenTLSWithCert(l net.Listener, certFile, keyFile string) error {
-	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	tlsConfig, tlsHandler, err := r.tlsConfig(certFile, keyFile)
 	if err != nil {
 		return err
 	}
 
-	tlsHandler := &fiber.TLSHandler{}
-	tlsConfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
-		Certificates: []tls.Certificate{
-			cert,
-		},
-		GetCertificate: tlsHandler.GetClientInfo,
+	l = r.wrapListener(l)
+
+	if err := r.hooks.executeOnRoute(); err != nil {
+		return err
 	}
 
 	r.registerFallback()
@@ -156,6 +170,12 @@ func (r *Route) ListenTLSWithCert(l net.Listener, certFile, keyFile string) erro
 	return r.instance.Listener(tls.NewListener(l, tlsConfig), listenConfig)
 }
 
+// Hooks gets the lifecycle hooks of the server
+// Hooks 获取服务器的生命周期钩子
+func (r *Route) Hooks() *Hooks {
+	return r.hooks
+}
+
 func (r *Route) Info(name string) contractshttp.Info {
 	routes := r.GetRoutes()
 
@@ -169,45 +189,78 @@ func (r *Route) Info(name string) contractshttp.Info {
 }
 
 func (r *Route) Recover(callback func(ctx contractshttp.Context, err any)) {
-	globalRecoverCallback = callback
-	if err := r.init(r.globalMiddleware); err != nil {
-		panic(err)
+	if r.started.Load() {
+		panic(errors.New("the recover callback can't be changed after the server is started"))
 	}
+
+	globalRecoverCallback = callback
 }
 
 // Run run server
 // Run 运行服务器
 func (r *Route) Run(host ...string) error {
+	if r.socketActivation() {
+		l, err := activatedListener("http", 0)
+		if err != nil {
+			return err
+		}
+
+		return r.Listen(l)
+	}
+
 	if len(host) == 0 {
-		defaultHost := r.config.GetString("http.host")
-		defaultPort := r.config.GetString("http.port")
-		if defaultPort == "" {
-			return errors.New("port can't be empty")
+		defaultHost, err := r.defaultHost("http")
+		if err != nil {
+			return err
 		}
-		completeHost := defaultHost + ":" + defaultPort
-		host = append(host, completeHost)
+		host = append(host, defaultHost)
 	}
 
-	r.registerFallback()
-	r.outputRoutes()
-	color.Green().Println("[HTTP] Listening on: " + str.Of(host[0]).Start("http://").String())
+	if r.useProxyProtocol {
+		l, err := r.listenAddr(host[0])
+		if err != nil {
+			return err
+		}
+
+		return r.Listen(l)
+	}
 
 	listenConfig := r.listenConfig
 	listenConfig.DisableStartupMessage = true
-	return r.instance.Listen(host[0], listenConfig)
+	addr, err := r.resolveAddr(host[0], &listenConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := r.hooks.executeOnRoute(); err != nil {
+		return err
+	}
+
+	r.registerFallback()
+	r.outputRoutes()
+	color.Green().Println("[HTTP] Listening on: " + listeningUrl("http://", host[0]))
+
+	return r.instance.Listen(addr, listenConfig)
 }
 
 // RunTLS run TLS server
 // RunTLS 运行 TLS 服务器
 func (r *Route) RunTLS(host ...string) error {
+	if r.socketActivation() {
+		l, err := activatedListener("https", 1)
+		if err != nil {
+			return err
+		}
+
+		return r.ListenTLS(l)
+	}
+
 	if len(host) == 0 {
-		defaultHost := r.config.GetString("http.tls.host")
-		defaultPort := r.config.GetString("http.tls.port")
-		if defaultPort == "" {
-			return errors.New("port can't be empty")
+		defaultHost, err := r.defaultHost("http.tls")
+		if err != nil {
+			return err
 		}
-		completeHost := defaultHost + ":" + defaultPort
-		host = append(host, completeHost)
+		host = append(host, defaultHost)
 	}
 
 	certFile := r.config.GetString("http.tls.ssl.cert")
@@ -226,21 +279,154 @@ func (r *Route) RunTLSWithCert(host, certFile, keyFile string) error {
 		return errors.New("certificate can't be empty")
 	}
 
-	r.registerFallback()
-	r.outputRoutes()
-	color.Green().Println("[HTTPS] Listening on: " + str.Of(host).Start("https://").String())
+	if r.useProxyProtocol {
+		l, err := r.listenAddr(host)
+		if err != nil {
+			return err
+		}
+
+		return r.ListenTLSWithCert(l, certFile, keyFile)
+	}
+
+	clientAuth, clientCAs, err := r.clientAuth()
+	if err != nil {
+		return err
+	}
 
 	listenConfig := r.listenConfig
 	listenConfig.DisableStartupMessage = true
 	listenConfig.CertFile = certFile
 	listenConfig.CertKeyFile = keyFile
-	return r.instance.Listen(host, listenConfig)
+	if clientAuth != tls.NoClientCert {
+		listenConfig.TLSConfigFunc = func(tlsConfig *tls.Config) {
+			tlsConfig.ClientAuth = clientAuth
+			tlsConfig.ClientCAs = clientCAs
+		}
+	}
+	addr, err := r.resolveAddr(host, &listenConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := r.hooks.executeOnRoute(); err != nil {
+		return err
+	}
+
+	r.registerFallback()
+	r.outputRoutes()
+	color.Green().Println("[HTTPS] Listening on: " + listeningUrl("https://", host))
+
+	return r.instance.Listen(addr, listenConfig)
 }
 
-// SetGlobalMiddleware sets global middleware
+// RunAll run HTTP and TLS server together, both listeners are served by the same application.
+// The HTTP listener only redirects to HTTPS when http.drivers.fiber.https_redirect is set to 301 or 308.
+// RunAll 同时运行 HTTP 与 TLS 服务器
+func (r *Route) RunAll() error {
+	if r.listenConfig.EnablePrefork {
+		return errors.New("prefork is not supported when running HTTP and TLS server together")
+	}
+
+	redirectCode := r.config.GetInt(fmt.Sprintf("http.drivers.%s.https_redirect", r.driver), 0)
+	if redirectCode != 0 && redirectCode != http.StatusMovedPermanently && redirectCode != http.StatusPermanentRedirect {
+		return fmt.Errorf("unsupported https redirect status: %d", redirectCode)
+	}
+
+	tlsConfig, tlsHandler, err := r.tlsConfig(r.config.GetString("http.tls.ssl.cert"), r.config.GetString("http.tls.ssl.key"))
+	if err != nil {
+		return err
+	}
+
+	httpListener, err := r.listener("http", 0)
+	if err != nil {
+		return err
+	}
+	tlsListener, err := r.listener("https", 1)
+	if err != nil {
+		_ = httpListener.Close()
+		return err
+	}
+
+	if err := r.hooks.executeOnRoute(); err != nil {
+		_ = httpListener.Close()
+		_ = tlsListener.Close()
+		return err
+	}
+
+	r.registerFallback()
+	r.outputRoutes()
+	color.Green().Println("[HTTP] Listening on: " + str.Of(httpListener.Addr().String()).Start("http://").String())
+	color.Green().Println("[HTTPS] Listening on: " + str.Of(tlsListener.Addr().String()).Start("https://").String())
+
+	r.instance.SetTLSHandler(tlsHandler)
+
+	var httpServer *fasthttp.Server
+	if redirectCode != 0 {
+		_, tlsPort, _ := net.SplitHostPort(tlsListener.Addr().String())
+		httpServer = &fasthttp.Server{
+			Handler:               httpsRedirectHandler(redirectCode, tlsPort),
+			NoDefaultServerHeader: true,
+		}
+		r.redirectServer = httpServer
+	}
+
+	errs := make(chan error, 1)
+	var server *fasthttp.Server
+	listenConfig := r.listenConfig
+	listenConfig.DisableStartupMessage = true
+	listenConfig.BeforeServeFunc = func(app *fiber.App) error {
+		// The HTTP listener joins the application server once routes are built,
+		// so both listeners share the handlers and are closed together on shutdown.
+		server = httpServer
+		if server == nil {
+			server = app.Server()
+		}
+
+		// fiber runs the OnListen hooks for the TLS listener only, the HTTP listener is served by the server directly.
+		r.started.Store(true)
+		host, port, _ := net.SplitHostPort(httpListener.Addr().String())
+		if err := r.hooks.executeOnListen(ListenInfo{Host: host, Port: port, TLS: false}); err != nil {
+			server = nil
+
+			return err
+		}
+
+		go func() {
+			err := server.Serve(httpListener)
+			if err != nil {
+				// The TLS server stops too, so the application doesn't keep running with one listener.
+				_ = tlsListener.Close()
+			}
+			errs <- err
+		}()
+
+		return nil
+	}
+
+	if err := r.instance.Listener(tls.NewListener(tlsListener, tlsConfig), listenConfig); err != nil {
+		if server == nil {
+			_ = httpListener.Close()
+
+			return err
+		}
+
+		// The HTTP server is serving already, it's stopped so neither it nor its listener leaks.
+		_ = server.Shutdown()
+		_ = httpListener.Close()
+		if httpErr := <-errs; httpErr != nil && !errors.Is(httpErr, net.ErrClosed) {
+			// The HTTP server failed first and stopped the TLS server.
+			return httpErr
+		}
+
+		return err
+	}
+
+	return <-errs
+}
+
+// SetGlobalMiddleware sets global middleware, it panics if the server is started
 func (r *Route) SetGlobalMiddleware(middlewares []contractshttp.Middleware) {
-	r.globalMiddleware = middlewares
-	if err := r.init(r.globalMiddleware); err != nil {
+	if err := r.replaceGlobalMiddleware(middlewares); err != nil {
 		panic(err)
 	}
 }
@@ -253,7 +439,15 @@ func (r *Route) Shutdown(ctx ...context.Context) error {
 		c = ctx[0]
 	}
 
-	return r.instance.ShutdownWithContext(c)
+	hookErr := r.hooks.executeOnShutdown()
+
+	if r.redirectServer != nil {
+		if err := r.redirectServer.ShutdownWithContext(c); err != nil {
+			return errors.Join(hookErr, err)
+		}
+	}
+
+	return errors.Join(hookErr, r.instance.ShutdownWithContext(c))
 }
 
 // ServeHTTP serve http request (Not support)
@@ -265,6 +459,10 @@ func (r *Route) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 // Test for unit test
 // Test 用于单元测试
 func (r *Route) Test(request *http.Request) (*http.Response, error) {
+	if err := r.hooks.executeOnRoute(); err != nil {
+		return nil, err
+	}
+
 	r.registerFallback()
 
 	return r.instance.Test(request, fiber.TestConfig{Timeout: 0})
@@ -305,25 +503,72 @@ func (r *Route) init(globalMiddleware []contractshttp.Middleware) error {
 		network = fiber.NetworkTCP4
 	}
 
+	r.useProxyProtocol = r.config.GetBool(fmt.Sprintf("http.drivers.%s.proxy_protocol", r.driver), false)
+	if r.useProxyProtocol {
+		if prefork {
+			return errors.New("proxy protocol is not supported in prefork mode")
+		}
+
+		trustedUpstreams, _ := r.config.Get(fmt.Sprintf("http.drivers.%s.proxy_protocol_trusted", r.driver)).([]string)
+		// Every client could spoof its IP via the header otherwise, which the IP filter and the throttle rely on.
+		if len(trustedUpstreams) == 0 {
+			return fmt.Errorf("http.drivers.%s.proxy_protocol_trusted is required when the proxy protocol is enabled", r.driver)
+		}
+		trusted, err := parseTrustedNetworks(trustedUpstreams)
+		if err != nil {
+			return err
+		}
+		r.proxyProtocolTrusted = trusted
+	}
+
 	var trustedProxies []string
 	if trustedProxiesConfig, ok := r.config.Get(fmt.Sprintf("http.drivers.%s.trusted_proxies", r.driver)).([]string); ok {
 		trustedProxies = trustedProxiesConfig
 	}
 
+	// Timeouts are configured in seconds, 0 means no timeout.
+	timeouts := make(map[string]time.Duration)
+	for _, name := range []string{"read_timeout", "write_timeout", "idle_timeout", "tcp_keepalive_period"} {
+		seconds := r.config.GetInt(fmt.Sprintf("http.drivers.%s.%s", r.driver, name), 0)
+		if seconds < 0 {
+			return fmt.Errorf("http.drivers.%s.%s can't be negative", r.driver, name)
+		}
+		timeouts[name] = time.Duration(seconds) * time.Second
+	}
+
+	concurrency := r.config.GetInt(fmt.Sprintf("http.drivers.%s.concurrency", r.driver), fiber.DefaultConcurrency)
+	if concurrency <= 0 {
+		return fmt.Errorf("http.drivers.%s.concurrency must be greater than 0", r.driver)
+	}
+
 	instance := fiber.New(fiber.Config{
-		Immutable:      immutable,
-		BodyLimit:      r.config.GetInt(fmt.Sprintf("http.drivers.%s.body_limit", r.driver), 4096) << 10,
-		ReadBufferSize: r.config.GetInt(fmt.Sprintf("http.drivers.%s.header_limit", r.driver), 4096),
-		JSONEncoder:    json.Marshal,
-		JSONDecoder:    json.Unmarshal,
-		Views:          views,
-		ProxyHeader:    r.config.GetString(fmt.Sprintf("http.drivers.%s.proxy_header", r.driver), ""),
-		TrustProxy:     r.config.GetBool(fmt.Sprintf("http.drivers.%s.enable_trusted_proxy_check", r.driver), false),
+		Immutable:         immutable,
+		BodyLimit:         r.config.GetInt(fmt.Sprintf("http.drivers.%s.body_limit", r.driver), 4096) << 10,
+		ReadBufferSize:    r.config.GetInt(fmt.Sprintf("http.drivers.%s.header_limit", r.driver), 4096),
+		JSONEncoder:       json.Marshal,
+		JSONDecoder:       json.Unmarshal,
+		Views:             views,
+		ProxyHeader:       r.config.GetString(fmt.Sprintf("http.drivers.%s.proxy_header", r.driver), ""),
+		TrustProxy:        r.config.GetBool(fmt.Sprintf("http.drivers.%s.enable_trusted_proxy_check", r.driver), false),
+		ReadTimeout:       timeouts["read_timeout"],
+		WriteTimeout:      timeouts["write_timeout"],
+		IdleTimeout:       timeouts["idle_timeout"],
+		Concurrency:       concurrency,
+		DisableKeepalive:  r.config.GetBool(fmt.Sprintf("http.drivers.%s.disable_keepalive", r.driver), false),
+		ServerHeader:      r.config.GetString(fmt.Sprintf("http.drivers.%s.server_header", r.driver), ""),
+		StrictRouting:     r.config.GetBool(fmt.Sprintf("http.drivers.%s.strict_routing", r.driver), false),
+		CaseSensitive:     r.config.GetBool(fmt.Sprintf("http.drivers.%s.case_sensitive", r.driver), false),
+		ReduceMemoryUsage: r.config.GetBool(fmt.Sprintf("http.drivers.%s.reduce_memory_usage", r.driver), false),
+		StreamRequestBody: r.config.GetBool(fmt.Sprintf("http.drivers.%s.stream_request_body", r.driver), false),
 		TrustProxyConfig: fiber.TrustProxyConfig{
 			Proxies: trustedProxies,
 		},
 	})
 
+	// TCP keep-alive is a fasthttp server option that fiber.Config doesn't expose.
+	instance.Server().TCPKeepalive = r.config.GetBool(fmt.Sprintf("http.drivers.%s.tcp_keepalive", r.driver), false)
+	instance.Server().TCPKeepalivePeriod = timeouts["tcp_keepalive_period"]
+
 	r.listenConfig = fiber.ListenConfig{
 		EnablePrefork:   prefork,
 		ListenerNetwork: network,
@@ -336,7 +581,21 @@ func (r *Route) init(globalMiddleware []contractshttp.Middleware) error {
 		}),
 	}
 
-	if debug {
+	// The method is overridden before the routing, so the route of the overridden method is matched.
+	if r.config.GetBool(fmt.Sprintf("http.drivers.%s.method_override", r.driver), false) {
+		handlers = append(handlers, methodOverride)
+	}
+
+	// The metrics endpoint is served before the other handlers, so the scrapes skip the global middleware.
+	metrics := r.config.GetBool(fmt.Sprintf("http.drivers.%s.metrics.enabled", r.driver), false)
+	if metrics {
+		handlers = append(handlers, metricsEndpoint(r.config.GetString(fmt.Sprintf("http.drivers.%s.metrics.path", r.driver), "/metrics")))
+	}
+
+	// The access log wraps the recover middleware, so the requests which panic are logged with their 500.
+	if r.config.GetBool(fmt.Sprintf("http.drivers.%s.log.enabled", r.driver), false) {
+		handlers = append(handlers, middlewareToFiberHandler(newAccessLogMiddleware(r.config, r.driver)))
+	} else if debug {
 		handlers = append(handlers, logger.New(logger.Config{
 			Format:     "[HTTP] ${time} | ${status} | ${latency} | ${ip} | ${method} | ${path}\n",
 			TimeZone:   r.config.GetString("app.timezone", "UTC"),
@@ -344,25 +603,250 @@ func (r *Route) init(globalMiddleware []contractshttp.Middleware) error {
 		}))
 	}
 
-	globalMiddleware = append([]contractshttp.Middleware{&recoverMiddleware{}}, globalMiddleware...)
+	if metrics {
+		handlers = append(handlers, middlewareToFiberHandler(Metrics()))
+	}
+
+	handlers = append(handlers, middlewareToFiberHandler(&recoverMiddleware{}))
+	globalHandlersOffset := len(handlers)
 	handlers = append(handlers, middlewaresToFiberHandlers(globalMiddleware)...)
 
 	for _, handler := range handlers {
 		instance.Use(handler)
 	}
 
-	r.Router = NewGroup(
-		r.config,
-		instance,
-		"",
-		[]contractshttp.Middleware{},
-		[]contractshttp.Middleware{},
-	)
+	// Hooks outlive the fiber instance, so they are kept when the instance is rebuilt.
+	if r.hooks == nil {
+		r.hooks = &Hooks{}
+	}
+	instance.Hooks().OnListen(func(data fiber.ListenData) error {
+		r.started.Store(true)
+
+		return r.hooks.executeOnListen(ListenInfo{
+			Host: data.Host,
+			Port: data.Port,
+			TLS:  data.TLS,
+		})
+	})
+
+	r.fallbackRegistered = false
+	r.globalMiddleware = globalMiddleware
+	r.globalHandlersOffset = globalHandlersOffset
+	r.Router = &Group{
+		config:          r.config,
+		hooks:           r.hooks,
+		instance:        instance,
+		middlewares:     []contractshttp.Middleware{},
+		lastMiddlewares: []contractshttp.Middleware{},
+	}
 	r.instance = instance
 
 	return nil
 }
 
+// replaceGlobalMiddleware swaps the global middleware in place, so the routes registered before are kept.
+// The global middleware is registered first in init, so the root middleware route is the first route of every method,
+// its handlers before globalHandlersOffset are the fiber recover, method override, metrics, logger and goravel recover
+// handlers.
+func (r *Route) replaceGlobalMiddleware(middlewares []contractshttp.Middleware) error {
+	if r.started.Load() {
+		return errors.New("global middleware can't be changed after the server is started")
+	}
+
+	handlers := middlewaresToFiberHandlers(middlewares)
+	end := r.globalHandlersOffset + len(r.globalMiddleware)
+	for _, stack := range r.instance.Stack() {
+		if len(stack) == 0 {
+			continue
+		}
+
+		root := stack[0]
+		root.Handlers = slices.Concat(root.Handlers[:r.globalHandlersOffset], handlers, root.Handlers[end:])
+	}
+	r.globalMiddleware = middlewares
+
+	return nil
+}
+
+// clientAuth resolves the client certificate policy from http.tls.client_auth.mode and http.tls.client_auth.ca.
+// The mode defaults to "require" when a client CA bundle is configured, otherwise no client certificate is asked for.
+// clientAuth 根据 http.tls.client_auth.mode 与 http.tls.client_auth.ca 解析客户端证书校验策略
+func (r *Route) clientAuth() (tls.ClientAuthType, *x509.CertPool, error) {
+	caFile := r.config.GetString("http.tls.client_auth.ca")
+	mode := r.config.GetString("http.tls.client_auth.mode")
+	if mode == "" {
+		if caFile == "" {
+			return tls.NoClientCert, nil, nil
+		}
+		mode = "require"
+	}
+
+	var clientAuth tls.ClientAuthType
+	switch mode {
+	case "none":
+		return tls.NoClientCert, nil, nil
+	case "request":
+		clientAuth = tls.RequestClientCert
+	case "require":
+		clientAuth = tls.RequireAndVerifyClientCert
+	case "verify_if_given":
+		clientAuth = tls.VerifyClientCertIfGiven
+	default:
+		return tls.NoClientCert, nil, fmt.Errorf("unsupported client auth mode: %s", mode)
+	}
+
+	if caFile == "" {
+		if clientAuth == tls.RequestClientCert {
+			return clientAuth, nil, nil
+		}
+
+		return tls.NoClientCert, nil, fmt.Errorf("client CA can't be empty when client auth mode is %s", mode)
+	}
+
+	pem, err := os.ReadFile(filepath.Clean(caFile))
+	if err != nil {
+		return tls.NoClientCert, nil, fmt.Errorf("read client CA failed: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pem) {
+		return tls.NoClientCert, nil, fmt.Errorf("parse client CA failed: %s", caFile)
+	}
+
+	return clientAuth, clientCAs, nil
+}
+
+// resolveAddr switches the listen config to a Unix domain socket when the address uses the unix: prefix.
+// resolveAddr 当地址使用 unix: 前缀时切换为 Unix 域套接字监听
+func (r *Route) resolveAddr(addr string, listenConfig *fiber.ListenConfig) (string, error) {
+	path, ok := unixSocketPath(addr)
+	if !ok {
+		return addr, nil
+	}
+	if listenConfig.EnablePrefork {
+		return "", errors.New("prefork is not supported on unix socket")
+	}
+
+	listenConfig.ListenerNetwork = fiber.NetworkUnix
+	listenConfig.UnixSocketFileMode = os.FileMode(r.config.GetInt(fmt.Sprintf("http.drivers.%s.unix_socket_mode", r.driver), 0770))
+
+	return path, nil
+}
+
+// socketActivation reports whether listeners are inherited from systemd socket activation.
+func (r *Route) socketActivation() bool {
+	return r.config.GetBool(fmt.Sprintf("http.drivers.%s.socket_activation", r.driver), false)
+}
+
+// defaultHost builds the listen address from the host and port config under the given key, e.g. http or http.tls.
+// defaultHost 根据配置生成监听地址
+func (r *Route) defaultHost(key string) (string, error) {
+	host := r.config.GetString(key + ".host")
+	port := r.config.GetString(key + ".port")
+	if _, ok := unixSocketPath(host); ok {
+		return host, nil
+	}
+	if port == "" {
+		return "", errors.New("port can't be empty")
+	}
+
+	return host + ":" + port, nil
+}
+
+// listener creates the listener for RunAll, honoring socket activation and Unix sockets.
+// listener 为 RunAll 创建监听器
+func (r *Route) listener(name string, position int) (net.Listener, error) {
+	if r.socketActivation() {
+		l, err := activatedListener(name, position)
+		if err != nil {
+			return nil, err
+		}
+
+		return r.wrapListener(l), nil
+	}
+
+	key := "http"
+	if name == "https" {
+		key = "http.tls"
+	}
+	host, err := r.defaultHost(key)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := r.listenAddr(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.wrapListener(l), nil
+}
+
+// listenAddr binds the address on the configured network, or on a Unix socket for unix: addresses.
+// listenAddr 在配置的网络上监听地址
+func (r *Route) listenAddr(host string) (net.Listener, error) {
+	listenConfig := r.listenConfig
+	addr, err := r.resolveAddr(host, &listenConfig)
+	if err != nil {
+		return nil, err
+	}
+	if listenConfig.ListenerNetwork == fiber.NetworkUnix {
+		if err := os.Remove(addr); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	l, err := net.Listen(listenConfig.ListenerNetwork, addr)
+	if err != nil {
+		return nil, err
+	}
+	if listenConfig.ListenerNetwork == fiber.NetworkUnix {
+		if err := os.Chmod(addr, listenConfig.UnixSocketFileMode); err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// wrapListener parses the PROXY protocol header on accepted connections when it is enabled.
+// wrapListener 启用 PROXY 协议时解析连接的协议头
+func (r *Route) wrapListener(l net.Listener) net.Listener {
+	if !r.useProxyProtocol {
+		return l
+	}
+
+	return newProxyProtocolListener(l, r.proxyProtocolTrusted)
+}
+
+// tlsConfig loads the certificate and the client certificate policy into a TLS config.
+// tlsConfig 加载证书及客户端证书校验策略
+func (r *Route) tlsConfig(certFile, keyFile string) (*tls.Config, *fiber.TLSHandler, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientAuth, clientCAs, err := r.clientAuth()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsHandler := &fiber.TLSHandler{}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		Certificates: []tls.Certificate{
+			cert,
+		},
+		GetCertificate: tlsHandler.GetClientInfo,
+		ClientAuth:     clientAuth,
+		ClientCAs:      clientCAs,
+	}
+
+	return tlsConfig, tlsHandler, nil
+}
+
 // outputRoutes output all routes
 // outputRoutes 输出所有路由
 func (r *Route) outputRoutes() {
@@ -377,9 +861,10 @@ func (r *Route) outputRoutes() {
 }
 
 func (r *Route) registerFallback() {
-	if r.fallback == nil {
+	if r.fallback == nil || r.fallbackRegistered {
 		return
 	}
+	r.fallbackRegistered = true
 
 	r.instance.Use(func(ctx fiber.Ctx) error {
 		if response := r.fallback(NewContext(ctx)); response != nil {
@@ -395,6 +880,29 @@ func (r *Route) setMiddlewares(middlewares []fiber.Handler) {
 	}
 }
 
+// httpsRedirectHandler redirects every request to the same host and URI over HTTPS.
+func httpsRedirectHandler(code int, tlsPort string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		host := string(ctx.Host())
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if tlsPort != "" && tlsPort != "443" {
+			host = net.JoinHostPort(host, tlsPort)
+		}
+
+		ctx.Redirect("https://"+host+string(ctx.RequestURI()), code)
+	}
+}
+
+func listeningUrl(scheme, addr string) string {
+	if _, ok := unixSocketPath(addr); ok {
+		return addr
+	}
+
+	return str.Of(addr).Start(scheme).String()
+}
+
 func defaultRecoverCallback(ctx contractshttp.Context, err any) {
 	LogFacade.WithContext(ctx).Request(ctx.Request()).Error(err)
 	ctx.Request().Abort(contractshttp.StatusInternalServerError)
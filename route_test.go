@@ -1,6 +1,7 @@
 package fiber
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -16,6 +19,7 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	contractshttp "github.com/goravel/framework/contracts/http"
+	contractsroute "github.com/goravel/framework/contracts/route"
 	"github.com/goravel/framework/contracts/validation"
 	mocksconfig "github.com/goravel/framework/mocks/config"
 	mockslog "github.com/goravel/framework/mocks/log"
@@ -48,7 +52,23 @@ func (s *RouteTestSuite) SetupTest() {
 	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
 	s.mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
 	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
 	s.mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
 	s.mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
 
 	s.mockLog = mockslog.NewLog(s.T())
@@ -84,17 +104,6 @@ func (s *RouteTestSuite) TestRecover() {
 	})
 
 	s.Run("with custom callback", func() {
-		s.mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
-		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
-		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
-		s.mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
-		s.mockConfig.EXPECT().GetInt("http.drivers.fiber.body_limit", 4096).Return(4096).Once()
-		s.mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
-		s.mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
-		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
-		s.mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
-		s.mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
-
 		globalRecoverCallback = func(ctx contractshttp.Context, err any) {
 			ctx.Request().Abort(http.StatusBadRequest)
 		}
@@ -150,6 +159,17 @@ func (s *RouteTestSuite) TestFallback() {
 	s.Equal("not found", string(body))
 }
 
+func (s *RouteTestSuite) TestRegisterFallbackOnce() {
+	s.route.Fallback(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusNotFound, "not found")
+	})
+
+	count := s.route.instance.HandlersCount()
+	s.route.registerFallback()
+	s.route.registerFallback()
+	s.Equal(count+1, s.route.instance.HandlersCount())
+}
+
 func (s *RouteTestSuite) TestGetRoutes() {
 	s.route.Get("/b/{id}", func(ctx contractshttp.Context) contractshttp.Response {
 		return ctx.Response().String(200, "ok")
@@ -172,20 +192,37 @@ func (s *RouteTestSuite) TestGetRoutes() {
 }
 
 func (s *RouteTestSuite) TestGlobalMiddleware() {
-	s.mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
-	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
-	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
-	s.mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
-	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.body_limit", 4096).Return(4096).Once()
-	s.mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
-	s.mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
-	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
-	s.mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
-	s.mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
+	s.route.Get("/global", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, "ok")
+	})
+
+	assertGlobal := func(expectHeader string) {
+		req := httptest.NewRequest("GET", "/global", nil)
+		req.Host = "example.com"
+		resp, err := s.route.Test(req)
+		s.Require().NoError(err)
+
+		body, err := io.ReadAll(resp.Body)
+		s.NoError(err)
+		s.Equal("ok", string(body))
+		s.Equal(expectHeader, resp.Header.Get("X-Global"))
+	}
+
+	s.route.GlobalMiddleware(&globalMwTestType{})
+	s.Len(s.route.GetGlobalMiddleware(), 1)
+	assertGlobal("goravel")
+
+	s.route.SetGlobalMiddleware(nil)
+	s.Empty(s.route.GetGlobalMiddleware())
+	assertGlobal("")
 
-	middleware := contractshttp.Middleware(&globalMwTestType{})
-	s.route.GlobalMiddleware(middleware)
-	s.Equal(uint32(4), s.route.instance.HandlersCount())
+	s.route.started.Store(true)
+	s.PanicsWithError("global middleware can't be changed after the server is started", func() {
+		s.route.GlobalMiddleware(&globalMwTestType{})
+	})
+	s.PanicsWithError("the recover callback can't be changed after the server is started", func() {
+		s.route.Recover(defaultRecoverCallback)
+	})
 }
 
 func (s *RouteTestSuite) TestNewRouteDefaultGlobalMiddleware() {
@@ -199,7 +236,23 @@ func (s *RouteTestSuite) TestNewRouteDefaultGlobalMiddleware() {
 	mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
 	mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
 	mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+	mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
 	mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
 	mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
 
 	route, err := NewRoute(mockConfig, map[string]any{"driver": "fiber"})
@@ -248,6 +301,8 @@ func (s *RouteTestSuite) TestListenTLS() {
 	})
 
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+	s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("").Once()
 	s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
 	s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
 
@@ -284,6 +339,8 @@ func (s *RouteTestSuite) TestListenTLSWithCert() {
 	})
 
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+	s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("").Once()
 
 	go func() {
 		l, err := net.Listen("tcp", host)
@@ -308,6 +365,76 @@ func (s *RouteTestSuite) TestListenTLSWithCert() {
 	s.Equal("{\"Hello\":\"Goravel\"}", string(body))
 }
 
+func (s *RouteTestSuite) TestListenTLSWithClientAuth() {
+	s.Run("require client certificate", func() {
+		host := "127.0.0.1:3103"
+
+		s.route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			subject, ok := ClientSubject(ctx)
+			if !ok || len(ClientCertificates(ctx)) != 1 {
+				return ctx.Response().String(http.StatusUnauthorized, "")
+			}
+
+			return ctx.Response().String(http.StatusOK, subject.CommonName)
+		})
+
+		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("test_ca.crt").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("require").Once()
+
+		go func() {
+			l, err := net.Listen("tcp", host)
+			s.NoError(err)
+			s.NoError(s.route.ListenTLSWithCert(l, "test_ca.crt", "test_ca.key"))
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		cert, err := tls.LoadX509KeyPair("test_ca.crt", "test_ca.key")
+		s.Require().NoError(err)
+
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{cert}},
+		}}
+		resp, err := client.Get("https://" + host)
+		s.Require().NoError(err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("goravel.dev", string(body))
+
+		client = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}}
+		_, err = client.Get("https://" + host)
+		s.Error(err)
+	})
+
+	s.Run("client CA is required", func() {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		s.Require().NoError(err)
+		defer func() {
+			_ = l.Close()
+		}()
+
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("verify_if_given").Once()
+
+		s.EqualError(s.route.ListenTLSWithCert(l, "test_ca.crt", "test_ca.key"), "client CA can't be empty when client auth mode is verify_if_given")
+	})
+
+	s.Run("unsupported mode", func() {
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("test_ca.crt").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("always").Once()
+
+		s.EqualError(s.route.RunTLSWithCert("127.0.0.1:3104", "test_ca.crt", "test_ca.key"), "unsupported client auth mode: always")
+	})
+}
+
 func (s *RouteTestSuite) TestInfo() {
 	action := s.route.Get("/test", func(ctx contractshttp.Context) contractshttp.Response {
 		return ctx.Response().Json(200, contractshttp.Json{
@@ -328,6 +455,7 @@ func (s *RouteTestSuite) TestInfo() {
 
 func (s *RouteTestSuite) TestRun() {
 	s.Run("error when default port is empty", func() {
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		s.mockConfig.EXPECT().GetString("http.host").Return("127.0.0.1").Once()
 		s.mockConfig.EXPECT().GetString("http.port").Return("").Once()
 
@@ -335,6 +463,7 @@ func (s *RouteTestSuite) TestRun() {
 	})
 
 	s.Run("use default host", func() {
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		host := "127.0.0.1"
 		port := "3031"
 		addr := host + ":" + port
@@ -369,6 +498,7 @@ func (s *RouteTestSuite) TestRun() {
 	})
 
 	s.Run("use custom host", func() {
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		host := "127.0.0.1"
 		port := "3032"
 		addr := host + ":" + port
@@ -399,10 +529,50 @@ func (s *RouteTestSuite) TestRun() {
 		s.NoError(err)
 		s.Equal("{\"Hello\":\"Goravel\"}", string(body))
 	})
+
+	s.Run("use unix socket", func() {
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
+		socket := filepath.Join(s.T().TempDir(), "goravel.sock")
+
+		s.route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Json(200, contractshttp.Json{
+				"Hello": "Goravel",
+			})
+		})
+
+		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetInt("http.drivers.fiber.unix_socket_mode", 0770).Return(0660).Once()
+
+		go func() {
+			s.NoError(s.route.Run("unix:" + socket))
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		info, err := os.Stat(socket)
+		s.Require().NoError(err)
+		s.Equal(os.FileMode(0660), info.Mode().Perm())
+
+		client := &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		}}
+		resp, err := client.Get("http://goravel/")
+		s.Require().NoError(err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		s.NoError(err)
+		s.Equal("{\"Hello\":\"Goravel\"}", string(body))
+	})
 }
 
 func (s *RouteTestSuite) TestRunTLS() {
 	s.Run("error when default port is empty", func() {
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		s.mockConfig.EXPECT().GetString("http.tls.host").Return("127.0.0.1").Once()
 		s.mockConfig.EXPECT().GetString("http.tls.port").Return("").Once()
 
@@ -410,6 +580,7 @@ func (s *RouteTestSuite) TestRunTLS() {
 	})
 
 	s.Run("use default host", func() {
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		host := "127.0.0.1"
 		port := "3033"
 		addr := host + ":" + port
@@ -421,6 +592,8 @@ func (s *RouteTestSuite) TestRunTLS() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("").Once()
 		s.mockConfig.EXPECT().GetString("http.tls.host").Return(host).Once()
 		s.mockConfig.EXPECT().GetString("http.tls.port").Return(port).Once()
 		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
@@ -449,6 +622,7 @@ func (s *RouteTestSuite) TestRunTLS() {
 	})
 
 	s.Run("use custom host", func() {
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		host := "127.0.0.1"
 		port := "3034"
 		addr := host + ":" + port
@@ -460,6 +634,8 @@ func (s *RouteTestSuite) TestRunTLS() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("").Once()
 		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
 		s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
 
@@ -486,6 +662,120 @@ func (s *RouteTestSuite) TestRunTLS() {
 	})
 }
 
+func (s *RouteTestSuite) TestRunAll() {
+	mockRunAll := func(httpPort, tlsPort string, redirectCode int) {
+		s.mockConfig.EXPECT().GetInt("http.drivers.fiber.https_redirect", 0).Return(redirectCode).Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("").Once()
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Twice()
+		s.mockConfig.EXPECT().GetString("http.host").Return("127.0.0.1").Once()
+		s.mockConfig.EXPECT().GetString("http.port").Return(httpPort).Once()
+		s.mockConfig.EXPECT().GetString("http.tls.host").Return("127.0.0.1").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.port").Return(tlsPort).Once()
+		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	s.Run("serve both listeners", func() {
+		s.route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().String("Goravel")
+		})
+
+		mockRunAll("3038", "3039", 0)
+
+		var (
+			listenInfos []ListenInfo
+			listenLock  sync.Mutex
+		)
+		s.route.Hooks().OnListen(func(info ListenInfo) error {
+			listenLock.Lock()
+			defer listenLock.Unlock()
+			listenInfos = append(listenInfos, info)
+			return nil
+		})
+
+		done := make(chan error, 1)
+		go func() {
+			done <- s.route.RunAll()
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		for _, addr := range []string{"http://127.0.0.1:3038", "https://127.0.0.1:3039"} {
+			resp, err := client.Get(addr)
+			s.Require().NoError(err)
+			body, err := io.ReadAll(resp.Body)
+			s.NoError(err)
+			s.NoError(resp.Body.Close())
+			s.Equal(http.StatusOK, resp.StatusCode)
+			s.Equal("Goravel", string(body))
+		}
+
+		listenLock.Lock()
+		s.ElementsMatch([]ListenInfo{
+			{Host: "127.0.0.1", Port: "3038", TLS: false},
+			{Host: "127.0.0.1", Port: "3039", TLS: true},
+		}, listenInfos)
+		listenLock.Unlock()
+
+		s.NoError(s.route.Shutdown())
+		s.NoError(<-done)
+
+		_, err := client.Get("http://127.0.0.1:3038")
+		s.Error(err)
+		_, err = client.Get("https://127.0.0.1:3039")
+		s.Error(err)
+	})
+
+	s.Run("redirect HTTP to HTTPS", func() {
+		s.SetupTest()
+		s.route.Get("/users", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().String("Goravel")
+		})
+
+		mockRunAll("3040", "3041", http.StatusPermanentRedirect)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- s.route.RunAll()
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		resp, err := client.Get("http://127.0.0.1:3040/users?page=1")
+		s.Require().NoError(err)
+		s.NoError(resp.Body.Close())
+		s.Equal(http.StatusPermanentRedirect, resp.StatusCode)
+		s.Equal("https://127.0.0.1:3041/users?page=1", resp.Header.Get("Location"))
+
+		resp, err = client.Get("https://127.0.0.1:3041/users")
+		s.Require().NoError(err)
+		s.NoError(resp.Body.Close())
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		s.NoError(s.route.Shutdown())
+		s.NoError(<-done)
+
+		_, err = client.Get("http://127.0.0.1:3040/users")
+		s.Error(err)
+	})
+
+	s.Run("unsupported redirect status", func() {
+		s.mockConfig.EXPECT().GetInt("http.drivers.fiber.https_redirect", 0).Return(http.StatusFound).Once()
+
+		s.EqualError(s.route.RunAll(), "unsupported https redirect status: 302")
+	})
+}
+
 func (s *RouteTestSuite) TestRunTLSWithCert() {
 	s.Run("error when default host is empty", func() {
 		s.Equal(errors.New("host can't be empty"), s.route.RunTLSWithCert("", "test_ca.crt", "test_ca.key"))
@@ -503,6 +793,8 @@ func (s *RouteTestSuite) TestRunTLSWithCert() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("").Once()
 
 		go func() {
 			s.NoError(s.route.RunTLSWithCert(addr, "test_ca.crt", "test_ca.key"))
@@ -537,6 +829,8 @@ func (s *RouteTestSuite) TestRunTLSWithCert() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.ca").Return("").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.client_auth.mode").Return("").Once()
 
 		go func() {
 			s.NoError(s.route.RunTLSWithCert(addr, "test_ca.crt", "test_ca.key"))
@@ -571,6 +865,7 @@ func (s *RouteTestSuite) TestShutdown() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		s.mockConfig.EXPECT().GetString("http.host").Return(host).Once()
 		s.mockConfig.EXPECT().GetString("http.port").Return(port).Once()
 
@@ -603,6 +898,7 @@ func (s *RouteTestSuite) TestShutdown() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
 		s.mockConfig.EXPECT().GetString("http.host").Return(host).Once()
 		s.mockConfig.EXPECT().GetString("http.port").Return(port).Once()
 
@@ -631,6 +927,96 @@ func (s *RouteTestSuite) TestShutdown() {
 	})
 }
 
+func (s *RouteTestSuite) TestHooks() {
+	var (
+		listenInfo ListenInfo
+		routeInfos []contractshttp.Info
+		groupInfos []GroupInfo
+		shutdown   bool
+	)
+
+	hooks := s.route.Hooks()
+	hooks.OnListen(func(info ListenInfo) error {
+		listenInfo = info
+		return nil
+	})
+	hooks.OnRoute(func(info contractshttp.Info) error {
+		routeInfos = append(routeInfos, info)
+		return nil
+	})
+	hooks.OnGroup(func(info GroupInfo) error {
+		groupInfos = append(groupInfos, info)
+		return nil
+	})
+	hooks.OnShutdown(func() error {
+		shutdown = true
+		return errors.New("deregister failed")
+	})
+
+	s.route.Prefix("hooks").Middleware(Cors()).Group(func(router contractsroute.Router) {
+		router.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().String("Goravel")
+		}).Name("hooks")
+	})
+
+	s.Len(groupInfos, 1)
+	s.Equal("/hooks", groupInfos[0].Prefix)
+	s.Len(groupInfos[0].Middleware, 1)
+	s.Empty(routeInfos)
+
+	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.fiber.socket_activation", false).Return(false).Once()
+	s.mockConfig.EXPECT().GetString("http.host").Return("127.0.0.1").Once()
+	s.mockConfig.EXPECT().GetString("http.port").Return("6790").Once()
+
+	go func() {
+		s.NoError(s.route.Run())
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	s.Equal(ListenInfo{Host: "127.0.0.1", Port: "6790", TLS: false}, listenInfo)
+	s.Len(routeInfos, 1)
+	s.Equal("GET|HEAD", routeInfos[0].Method)
+	s.Equal("/hooks/", routeInfos[0].Path)
+	s.Equal("hooks", routeInfos[0].Name)
+	s.EqualError(s.route.Shutdown(), "deregister failed")
+	s.True(shutdown)
+}
+
+func (s *RouteTestSuite) TestRouteHookError() {
+	var (
+		paths  []string
+		failed bool
+	)
+	s.route.Hooks().OnRoute(func(info contractshttp.Info) error {
+		paths = append(paths, info.Path)
+		if info.Path == "/invalid" && !failed {
+			failed = true
+			return errors.New("invalid route")
+		}
+		return nil
+	})
+
+	s.route.Get("/invalid", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("Goravel")
+	})
+
+	_, err := s.route.Test(httptest.NewRequest("GET", "/invalid", nil))
+	s.EqualError(err, "invalid route")
+
+	// The failed route is kept, so it's passed to the hooks again.
+
+	s.route.Get("/valid", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("Goravel")
+	})
+
+	resp, err := s.route.Test(httptest.NewRequest("GET", "/valid", nil))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal([]string{"/invalid", "/invalid", "/valid"}, paths)
+}
+
 func TestNewRoute(t *testing.T) {
 	var mockConfig *mocksconfig.Config
 
@@ -639,6 +1025,7 @@ func TestNewRoute(t *testing.T) {
 		parameters     map[string]any
 		setup          func()
 		expectTemplate fiber.Views
+		expectConfig   func(config fiber.Config)
 		expectError    error
 	}{
 		{
@@ -659,9 +1046,102 @@ func TestNewRoute(t *testing.T) {
 				mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
 				mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
 				mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(fiber.DefaultConcurrency).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(false).Once()
+				mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("").Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
+				mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
+			},
+		},
+		{
+			name:       "server config",
+			parameters: map[string]any{"driver": "fiber"},
+			setup: func() {
+				mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(3).Once()
+				mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
+				mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.body_limit", 4096).Return(4096).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.header_limit", 4096).Return(4096).Once()
+				mockConfig.EXPECT().GetString("http.drivers.fiber.proxy_header", "").Return("X-Forwarded-For").Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.enable_trusted_proxy_check", false).Return(false).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(10).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(20).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(30).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(1024).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.disable_keepalive", false).Return(true).Once()
+				mockConfig.EXPECT().GetString("http.drivers.fiber.server_header", "").Return("Goravel").Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.strict_routing", false).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.case_sensitive", false).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.reduce_memory_usage", false).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.stream_request_body", false).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.tcp_keepalive", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
 				mockConfig.EXPECT().GetBool("app.debug", false).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.log.enabled", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.metrics.enabled", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.method_override", false).Return(false).Once()
 				mockConfig.EXPECT().GetString("app.timezone", "UTC").Return("UTC").Once()
 			},
+			expectConfig: func(config fiber.Config) {
+				assert.Equal(t, 10*time.Second, config.ReadTimeout)
+				assert.Equal(t, 20*time.Second, config.WriteTimeout)
+				assert.Equal(t, 30*time.Second, config.IdleTimeout)
+				assert.Equal(t, 1024, config.Concurrency)
+				assert.True(t, config.DisableKeepalive)
+				assert.Equal(t, "Goravel", config.ServerHeader)
+				assert.True(t, config.StrictRouting)
+				assert.True(t, config.CaseSensitive)
+				assert.True(t, config.ReduceMemoryUsage)
+				assert.True(t, config.StreamRequestBody)
+			},
+		},
+		{
+			name:       "negative timeout",
+			parameters: map[string]any{"driver": "fiber"},
+			setup: func() {
+				mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(3).Once()
+				mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
+				mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(-1).Once()
+			},
+			expectError: errors.New("http.drivers.fiber.read_timeout can't be negative"),
+		},
+		{
+			name:       "invalid concurrency",
+			parameters: map[string]any{"driver": "fiber"},
+			setup: func() {
+				mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(3).Once()
+				mockConfig.EXPECT().Get("http.drivers.fiber.template").Return(nil).Twice()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.immutable", true).Return(true).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.prefork", false).Return(false).Once()
+				mockConfig.EXPECT().GetBool("http.drivers.fiber.proxy_protocol", false).Return(false).Once()
+				mockConfig.EXPECT().Get("http.drivers.fiber.trusted_proxies").Return(nil).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.read_timeout", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.write_timeout", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.idle_timeout", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.tcp_keepalive_period", 0).Return(0).Once()
+				mockConfig.EXPECT().GetInt("http.drivers.fiber.concurrency", fiber.DefaultConcurrency).Return(0).Once()
+			},
+			expectError: errors.New("http.drivers.fiber.concurrency must be greater than 0"),
 		},
 	}
 
@@ -677,6 +1157,9 @@ func TestNewRoute(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, route)
+				if test.expectConfig != nil {
+					test.expectConfig(route.instance.Config())
+				}
 			}
 		})
 	}
@@ -701,7 +1184,10 @@ func assertHttpNormal(t *testing.T, addr string, expectNormal bool) {
 
 type globalMwTestType struct{}
 
-func (m *globalMwTestType) Handle(contractshttp.Context) {}
+func (m *globalMwTestType) Handle(ctx contractshttp.Context) {
+	ctx.Response().Header("X-Global", "goravel")
+	ctx.Request().Next()
+}
 
 func (m *globalMwTestType) Signature() string { return "test_global_mw" }
 
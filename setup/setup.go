@@ -20,9 +20,80 @@ func main() {
         "prefork": false,
         "body_limit": 4096,
         "header_limit": 4096,
+        // the maximum duration in seconds for reading the full request, writing the response and waiting for
+        // the next request when keep-alive is enabled, 0 means no timeout
+        "read_timeout": 0,
+        "write_timeout": 0,
+        "idle_timeout": 0,
+        // the maximum number of concurrent connections
+        "concurrency": 262144,
+        // close the connection after sending the response instead of keeping it alive
+        "disable_keepalive": false,
+        // enable TCP keep-alive probes, the period in seconds is left to the OS when it is 0
+        "tcp_keepalive": false,
+        "tcp_keepalive_period": 0,
+        // the value of the Server response header, empty omits the header
+        "server_header": "",
+        // treat /foo and /foo/ as different routes
+        "strict_routing": false,
+        // treat /Foo and /foo as different routes
+        "case_sensitive": false,
+        // aggressively reduce memory usage at the cost of higher CPU usage
+        "reduce_memory_usage": false,
+        // stream the request body instead of buffering it, large bodies are no longer rejected by body_limit
+        "stream_request_body": false,
+        // the permission of the socket file when http.host is a Unix socket, e.g. unix:/run/goravel.sock
+        "unix_socket_mode": 0770,
+        // serve the listeners passed by systemd socket activation (LISTEN_FDS) instead of binding http.host
+        "socket_activation": false,
+        // the status (301 or 308) the HTTP listener redirects to HTTPS with when using RunAll, 0 serves routes on both
+        "https_redirect": 0,
+        // parse the PROXY protocol v1/v2 header sent by load balancers such as AWS NLB or HAProxy in TCP mode,
+        // not supported in prefork mode
+        "proxy_protocol": false,
+        // the upstream IPs or CIDRs allowed to send the PROXY protocol header, required when proxy_protocol is enabled
+        "proxy_protocol_trusted": []string{},
+        // rewrite the method of POST requests from the _method form field or the X-HTTP-Method-Override header,
+        // so HTML forms reach the PUT, PATCH and DELETE routes
+        "method_override": false,
+        // serve the precompressed siblings of the static files, e.g. app.js.br for app.js, to the clients accepting
+        // their encoding
+        "static_precompressed": false,
+        // structured access logs written via the log facade, they replace the text logs of the debug mode
+        "log": map[string]any{
+            "enabled": false,
+            // the ratio of the requests to log, server errors and slow requests are always logged
+            "sample_rate": 1.0,
+            // the paths not logged, e.g. "health" or "metrics/*"
+            "except": []string{},
+            // the latency in milliseconds above which requests are logged as warnings, 0 disables it
+            "slow_threshold": 0,
+        },
+        // request metrics in the Prometheus text format, served on the path without the global middleware
+        "metrics": map[string]any{
+            "enabled": false,
+            "path":    "/metrics",
+        },
+        // served while the application is down via artisan down, besides the health endpoints and the secret
+        // bypass path /maintenance/<secret>
+        "maintenance": map[string]any{
+            // the IPs or CIDRs allowed to use the application, e.g. the office network
+            "allowed_ips": []string{},
+            // the paths served, e.g. "webhooks/*"
+            "except": []string{},
+            // the Retry-After header in seconds
+            "retry_after": 60,
+        },
         "route": func() (route.Route, error) {
             return fiberfacades.Route("fiber"), nil
         },
+    }`
+	clientAuthConfig := `map[string]any{
+        // the client certificate policy of the TLS server: none, request, require or verify_if_given,
+        // empty means require when ca is set, otherwise none
+        "mode": "",
+        // the CA bundle the client certificates are verified against, e.g. for mutual TLS between services
+        "ca": "",
     }`
 	moduleImport := setup.Paths().Module().Import()
 	fiberServiceProvider := "&fiber.ServiceProvider{}"
@@ -32,6 +103,7 @@ func main() {
 	fiberFacade := "github.com/goravel/fiber/facades"
 	httpDriversConfig := match.Config("http.drivers")
 	httpConfig := match.Config("http")
+	httpTLSConfig := match.Config("http.tls")
 
 	setup.Install(
 		// Add fiber service provider to app.go if not using bootstrap setup
@@ -54,11 +126,13 @@ func main() {
 				modify.AddImport(fiberFacade, "fiberfacades"),
 			).
 			Find(httpDriversConfig).Modify(modify.AddConfig("fiber", config)).
+			Find(httpTLSConfig).Modify(modify.AddConfig("client_auth", clientAuthConfig)).
 			Find(httpConfig).Modify(modify.AddConfig("default", `"fiber"`)),
 	).Uninstall(
 		// Remove fiber config from http.go
 		modify.GoFile(httpConfigPath).
 			Find(httpDriversConfig).Modify(modify.RemoveConfig("fiber")).
+			Find(httpTLSConfig).Modify(modify.RemoveConfig("client_auth")).
 			Find(httpConfig).Modify(modify.AddConfig("default", `""`)).
 			Find(match.Imports()).
 			Modify(
@@ -0,0 +1,292 @@
+package fiber
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps the expiring values and counters shared by the Throttle, ResponseCache and Idempotency middleware.
+type Store interface {
+	// Get returns the value of the key, nil if it doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores the value of the key, it expires after ttl.
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Add stores the value only if the key doesn't exist and reports whether it's stored, it expires after ttl.
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Forget removes the key.
+	Forget(ctx context.Context, key string) error
+	// Increment increments the counter of the key and returns the new value,
+	// the counter expires after ttl from its first increment.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// GetInt64 returns the counter of the key, 0 if it doesn't exist.
+	GetInt64(ctx context.Context, key string) (int64, error)
+}
+
+// MemoryStoreConfig defines the config for the memory store.
+type MemoryStoreConfig struct {
+	// MaxEntries is the number of keys kept, the least recently used keys are evicted beyond it.
+	//
+	// Default: 10000
+	MaxEntries int
+
+	// MaxBytes is the size of the keys and values kept, the least recently used keys are evicted beyond it.
+	//
+	// Default: 64 MiB
+	MaxBytes int
+}
+
+// counterSize is the size a counter accounts for in MemoryStoreConfig.MaxBytes.
+const counterSize = 8
+
+type memoryStoreItem struct {
+	key       string
+	value     []byte
+	counter   int64
+	expiresAt time.Time
+}
+
+func (i *memoryStoreItem) size() int {
+	return len(i.key) + len(i.value) + counterSize
+}
+
+// memoryStore keeps the values in the process memory, expired values are swept once per minute.
+type memoryStore struct {
+	config    MemoryStoreConfig
+	mu        sync.Mutex
+	items     map[string]*list.Element
+	recent    *list.List
+	bytes     int
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates a store which keeps the values in the process memory.
+func NewMemoryStore(config ...MemoryStoreConfig) Store {
+	cfg := MemoryStoreConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 10000
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = 64 << 20
+	}
+
+	return &memoryStore{
+		config:    cfg,
+		items:     make(map[string]*list.Element),
+		recent:    list.New(),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.get(key, time.Now())
+	if item == nil {
+		return nil, nil
+	}
+
+	return item.value, nil
+}
+
+func (s *memoryStore) Put(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.set(&memoryStoreItem{key: key, value: value, expiresAt: now.Add(ttl)}, now)
+
+	return nil
+}
+
+func (s *memoryStore) Add(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.get(key, now) != nil {
+		return false, nil
+	}
+	s.set(&memoryStoreItem{key: key, value: value, expiresAt: now.Add(ttl)}, now)
+
+	return true, nil
+}
+
+func (s *memoryStore) Forget(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.items[key]; ok {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Increment(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if item := s.get(key, now); item != nil {
+		item.counter++
+
+		return item.counter, nil
+	}
+	s.set(&memoryStoreItem{key: key, counter: 1, expiresAt: now.Add(ttl)}, now)
+
+	return 1, nil
+}
+
+func (s *memoryStore) GetInt64(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.get(key, time.Now())
+	if item == nil {
+		return 0, nil
+	}
+
+	return item.counter, nil
+}
+
+// get returns the unexpired item of the key and marks it as recently used.
+func (s *memoryStore) get(key string, now time.Time) *memoryStoreItem {
+	element, ok := s.items[key]
+	if !ok {
+		return nil
+	}
+
+	item := element.Value.(*memoryStoreItem)
+	if !now.Before(item.expiresAt) {
+		s.remove(element)
+
+		return nil
+	}
+	s.recent.MoveToFront(element)
+
+	return item
+}
+
+// set replaces the item of the key, then evicts the least recently used items beyond the limits.
+func (s *memoryStore) set(item *memoryStoreItem, now time.Time) {
+	s.sweep(now)
+
+	if element, ok := s.items[item.key]; ok {
+		s.remove(element)
+	}
+	if item.size() > s.config.MaxBytes {
+		return
+	}
+
+	s.items[item.key] = s.recent.PushFront(item)
+	s.bytes += item.size()
+
+	for len(s.items) > s.config.MaxEntries || s.bytes > s.config.MaxBytes {
+		s.remove(s.recent.Back())
+	}
+}
+
+func (s *memoryStore) remove(element *list.Element) {
+	item := s.recent.Remove(element).(*memoryStoreItem)
+	delete(s.items, item.key)
+	s.bytes -= item.size()
+}
+
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	for _, element := range s.items {
+		if !now.Before(element.Value.(*memoryStoreItem).expiresAt) {
+			s.remove(element)
+		}
+	}
+	s.lastSweep = now
+}
+
+// cacheStore keeps the values in the framework cache, so they are shared by all instances.
+type cacheStore struct {
+	store string
+}
+
+// NewCacheStore creates a store which keeps the values in the framework cache,
+// the default cache store is used if no store name is given.
+func NewCacheStore(store ...string) Store {
+	s := &cacheStore{}
+	if len(store) > 0 {
+		s.store = store[0]
+	}
+
+	return s
+}
+
+func (s *cacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	driver, err := cacheDriver(ctx, s.store)
+	if err != nil {
+		return nil, err
+	}
+
+	value := driver.GetString(key, "")
+	if value == "" {
+		return nil, nil
+	}
+
+	return []byte(value), nil
+}
+
+func (s *cacheStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	driver, err := cacheDriver(ctx, s.store)
+	if err != nil {
+		return err
+	}
+
+	return driver.Put(key, string(value), ttl)
+}
+
+func (s *cacheStore) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	driver, err := cacheDriver(ctx, s.store)
+	if err != nil {
+		return false, err
+	}
+
+	return driver.Add(key, string(value), ttl), nil
+}
+
+func (s *cacheStore) Forget(ctx context.Context, key string) error {
+	driver, err := cacheDriver(ctx, s.store)
+	if err != nil {
+		return err
+	}
+
+	driver.Forget(key)
+
+	return nil
+}
+
+func (s *cacheStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	driver, err := cacheDriver(ctx, s.store)
+	if err != nil {
+		return 0, err
+	}
+
+	// Add sets the expiration only when the counter doesn't exist yet, the increment keeps it.
+	driver.Add(key, 0, ttl)
+
+	return driver.Increment(key)
+}
+
+func (s *cacheStore) GetInt64(ctx context.Context, key string) (int64, error) {
+	driver, err := cacheDriver(ctx, s.store)
+	if err != nil {
+		return 0, err
+	}
+
+	return driver.GetInt64(key, 0), nil
+}
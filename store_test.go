@@ -0,0 +1,182 @@
+package fiber
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockscache "github.com/goravel/framework/mocks/cache"
+	mocksfoundation "github.com/goravel/framework/mocks/foundation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	value, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, store.Put(ctx, "a", []byte("goravel"), time.Minute))
+	value, err = store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("goravel"), value)
+
+	added, err := store.Add(ctx, "a", []byte("framework"), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	require.NoError(t, store.Forget(ctx, "a"))
+	added, err = store.Add(ctx, "a", []byte("framework"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	require.NoError(t, store.Put(ctx, "expired", []byte("goravel"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	value, err = store.Get(ctx, "expired")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	added, err = store.Add(ctx, "expired", []byte("goravel"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	hits, err := store.Increment(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), hits)
+
+	hits, err = store.Increment(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), hits)
+
+	hits, err = store.GetInt64(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), hits)
+
+	hits, err = store.GetInt64(ctx, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), hits)
+
+	_, err = store.Increment(ctx, "expired counter", time.Nanosecond)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+
+	hits, err = store.GetInt64(ctx, "expired counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), hits)
+
+	hits, err = store.Increment(ctx, "expired counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), hits)
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		config        MemoryStoreConfig
+		expectKept    []string
+		expectEvicted []string
+	}{
+		{
+			name:          "max entries",
+			config:        MemoryStoreConfig{MaxEntries: 2},
+			expectKept:    []string{"a", "c"},
+			expectEvicted: []string{"b"},
+		},
+		{
+			name:          "max bytes",
+			config:        MemoryStoreConfig{MaxBytes: 2 * (1 + 7 + counterSize)},
+			expectKept:    []string{"a", "c"},
+			expectEvicted: []string{"b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore(test.config)
+
+			require.NoError(t, store.Put(ctx, "a", []byte("goravel"), time.Minute))
+			require.NoError(t, store.Put(ctx, "b", []byte("goravel"), time.Minute))
+			// Reading a marks it as recently used, so b is evicted first.
+			_, err := store.Get(ctx, "a")
+			require.NoError(t, err)
+			require.NoError(t, store.Put(ctx, "c", []byte("goravel"), time.Minute))
+
+			for _, key := range test.expectKept {
+				value, err := store.Get(ctx, key)
+				require.NoError(t, err)
+				assert.Equal(t, []byte("goravel"), value, key)
+			}
+			for _, key := range test.expectEvicted {
+				value, err := store.Get(ctx, key)
+				require.NoError(t, err)
+				assert.Nil(t, value, key)
+			}
+		})
+	}
+
+	store := NewMemoryStore(MemoryStoreConfig{MaxBytes: 16})
+	require.NoError(t, store.Put(ctx, "a", []byte("larger than the store"), time.Minute))
+	value, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestCacheStore(t *testing.T) {
+	defer func() {
+		App = nil
+	}()
+
+	ctx := context.Background()
+
+	App = nil
+	_, err := NewCacheStore().Get(ctx, "a")
+	assert.EqualError(t, err, "the application is not set")
+
+	mockApp := mocksfoundation.NewApplication(t)
+	mockCache := mockscache.NewCache(t)
+	mockDriver := mockscache.NewDriver(t)
+	App = mockApp
+
+	mockApp.EXPECT().MakeCache().Return(mockCache).Times(7)
+	mockCache.EXPECT().Store("redis").Return(mockDriver).Times(7)
+	mockDriver.EXPECT().WithContext(ctx).Return(mockDriver).Times(7)
+	mockDriver.EXPECT().GetString("a", "").Return("").Once()
+	mockDriver.EXPECT().Put("a", "goravel", time.Minute).Return(nil).Once()
+	mockDriver.EXPECT().GetString("a", "").Return("goravel").Once()
+	mockDriver.EXPECT().Add("lock", "1", time.Minute).Return(true).Once()
+	mockDriver.EXPECT().Forget("lock").Return(true).Once()
+	mockDriver.EXPECT().Add("counter", 0, time.Minute).Return(true).Once()
+	mockDriver.EXPECT().Increment("counter").Return(int64(1), nil).Once()
+	mockDriver.EXPECT().GetInt64("counter", int64(0)).Return(int64(1)).Once()
+
+	store := NewCacheStore("redis")
+
+	value, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, store.Put(ctx, "a", []byte("goravel"), time.Minute))
+
+	value, err = store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("goravel"), value)
+
+	added, err := store.Add(ctx, "lock", []byte("1"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	require.NoError(t, store.Forget(ctx, "lock"))
+
+	hits, err := store.Increment(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), hits)
+
+	hits, err = store.GetInt64(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), hits)
+}
@@ -1,11 +1,14 @@
 package fiber
 
 import (
+	"context"
 	"errors"
+	"path"
 	"regexp"
 	"strings"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/goravel/framework/contracts/cache"
 	httpcontract "github.com/goravel/framework/contracts/http"
 )
 
@@ -50,6 +53,10 @@ func handlerToFiberHandler(handler httpcontract.HandlerFunc) fiber.Handler {
 
 func middlewareToFiberHandler(middleware httpcontract.Middleware) fiber.Handler {
 	return func(c fiber.Ctx) error {
+		if skipBeforeRevalidation(c, middleware) {
+			return c.Next()
+		}
+
 		context := NewContext(c)
 		defer releaseContext(context)
 
@@ -127,3 +134,58 @@ func isSameMiddleware(a, b any) bool {
 	}
 	return mA.Signature() == mB.Signature()
 }
+
+// cacheDriver resolves the cache store with the name, the default store is used if the name is empty.
+// The cache is resolved on every call, so the stores backed by it can be created before the cache is booted.
+func cacheDriver(ctx context.Context, store string) (cache.Driver, error) {
+	if App == nil {
+		return nil, errors.New("the application is not set")
+	}
+
+	cacheFacade := App.MakeCache()
+	if cacheFacade == nil {
+		return nil, errors.New("the cache facade is not set")
+	}
+
+	var driver cache.Driver = cacheFacade
+	if store != "" {
+		driver = cacheFacade.Store(store)
+	}
+
+	return driver.WithContext(ctx), nil
+}
+
+// trimPathPatterns prepares the patterns for matchPathPatterns.
+func trimPathPatterns(patterns []string) []string {
+	trimmed := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		trimmed = append(trimmed, strings.Trim(pattern, "/"))
+	}
+
+	return trimmed
+}
+
+// matchPathPatterns matches the path against the patterns via path.Match without the leading and trailing slashes,
+// e.g. "webhooks/*".
+func matchPathPatterns(patterns []string, currentPath string) bool {
+	currentPath = strings.Trim(currentPath, "/")
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, currentPath); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchedRoute is the path template of the route which served the request, it's empty if no route matched.
+// It's resolved after the handlers ran, a request which doesn't match a route only reaches the root middleware
+// route, e.g. the global middleware or the fallback.
+func matchedRoute(c fiber.Ctx) string {
+	route := c.Route()
+	if !c.Matched() && route.Path == "/" {
+		return ""
+	}
+
+	return colonToBracket(route.Path)
+}
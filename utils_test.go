@@ -1,8 +1,10 @@
 package fiber
 
 import (
+	"net/http"
 	"testing"
 
+	"github.com/gofiber/fiber/v3"
 	contractshttp "github.com/goravel/framework/contracts/http"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,3 +33,48 @@ type testMiddleware struct{ id string }
 func (m *testMiddleware) Handle(contractshttp.Context) {}
 
 func (m *testMiddleware) Signature() string { return m.id }
+
+// newMiddlewareTestApp creates a fiber app which serves all paths and methods with the handler behind the given middleware,
+// the handler responds "ok" if it's nil.
+func newMiddlewareTestApp(handler contractshttp.HandlerFunc, middlewares ...contractshttp.Middleware) *fiber.App {
+	return newTrustedMiddlewareTestApp(false, handler, middlewares...)
+}
+
+// newTrustedMiddlewareTestApp is newMiddlewareTestApp which trusts the test connection as a proxy if trustProxy is true,
+// so X-Forwarded-* headers are honored and the client IP is read from X-Forwarded-For.
+func newTrustedMiddlewareTestApp(trustProxy bool, handler contractshttp.HandlerFunc, middlewares ...contractshttp.Middleware) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ProxyHeader: fiber.HeaderXForwardedFor,
+		TrustProxy:  trustProxy,
+		// The test connection comes from 0.0.0.0.
+		TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0"}},
+	})
+	handlers := middlewaresToFiberHandlers(middlewares)
+	handlers = append(handlers, handlerToFiberHandler(testHandlerOrOK(handler)))
+	first, rest := fiberHandlerArgs(handlers)
+	app.All("/*", first, rest...)
+
+	return app
+}
+
+// newGlobalMiddlewareTestApp registers the middleware as global middleware and the handler as the route of "/", so
+// the requests to the other paths don't match a route.
+func newGlobalMiddlewareTestApp(handler contractshttp.HandlerFunc, middlewares ...contractshttp.Middleware) *fiber.App {
+	app := fiber.New()
+	for _, middleware := range middlewaresToFiberHandlers(middlewares) {
+		app.Use(middleware)
+	}
+	app.Get("/", handlerToFiberHandler(testHandlerOrOK(handler)))
+
+	return app
+}
+
+func testHandlerOrOK(handler contractshttp.HandlerFunc) contractshttp.HandlerFunc {
+	if handler != nil {
+		return handler
+	}
+
+	return func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, "ok")
+	}
+}
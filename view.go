@@ -21,12 +21,19 @@ func NewView(instance fiber.Ctx, ctx *Context) *View {
 func (receive *View) Make(view string, data ...any) contractshttp.Response {
 	shared := ViewFacade.GetShared()
 	if receive.ctx != nil {
-		if session, ok := receive.ctx.values[sessionKey]; ok && session != nil {
+		values := receive.ctx.sharedValues()
+		if session, ok := values[sessionKey]; ok && session != nil {
 			if sessionValue, ok := session.(contractsession.Session); ok {
 				token := sessionValue.Token()
 				shared["csrf_token"] = token
 			}
 		}
+		if nonce, ok := values[cspNonceKey].(string); ok {
+			if shared == nil {
+				shared = make(map[string]any)
+			}
+			shared["csp_nonce"] = nonce
+		}
 	}
 	if len(data) == 0 {
 		return &HtmlResponse{shared, receive.instance, view}